@@ -40,6 +40,8 @@ var goplsStar = map[prop]int{
 	{"Hover", "range"}:                    wantOpt,     // complex expressions
 	{"InlayHint", "kind"}:                 wantOpt,     // temporary variables
 
+	{"SignatureInformation", "activeParameter"}: wantOptStar, // 0 must not fall back to SignatureHelp.activeParameter
+
 	{"TextDocumentClientCapabilities", "codeAction"}:          wantOpt,     // A.B.C.D
 	{"TextDocumentClientCapabilities", "completion"}:          wantOpt,     // A.B.C.D
 	{"TextDocumentClientCapabilities", "documentSymbol"}:      wantOpt,     // A.B.C.D
@@ -68,7 +70,6 @@ var renameProp = map[prop]string{
 	{"ExecuteCommandParams", "arguments"}: "[]json.RawMessage",
 	{"FoldingRange", "kind"}:              "string",
 	{"Hover", "contents"}:                 "MarkupContent",
-	{"InlayHint", "label"}:                "[]InlayHintLabelPart",
 
 	{"RelatedFullDocumentDiagnosticReport", "relatedDocuments"}:      "map[DocumentUri]interface{}",
 	{"RelatedUnchangedDocumentDiagnosticReport", "relatedDocuments"}: "map[DocumentUri]interface{}",
@@ -9,6 +9,7 @@ import (
 
 	"github.com/isaacphi/mcp-language-server/integrationtests/tests/clangd/internal"
 	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
 	"github.com/isaacphi/mcp-language-server/internal/tools"
 )
 
@@ -76,8 +77,8 @@ func TestFindReferences(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			// Call the FindReferences tool
-			result, err := tools.FindReferences(ctx, suite.Client, tc.symbolName)
+			// Call the FindReferencesAcrossServers tool
+			result, err := tools.FindReferencesAcrossServers(ctx, []*lsp.Client{suite.Client}, tc.symbolName)
 			if err != nil {
 				t.Fatalf("Failed to find references for %s: %v. Result: %s", tc.symbolName, err, result)
 			}
@@ -2,12 +2,14 @@ package references_test
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
 	"github.com/isaacphi/mcp-language-server/integrationtests/tests/go/internal"
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
 	"github.com/isaacphi/mcp-language-server/internal/tools"
 )
 
@@ -93,8 +95,8 @@ func TestFindReferences(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			// Call the FindReferences tool
-			result, err := tools.FindReferences(ctx, suite.Client, tc.symbolName)
+			// Call the FindReferencesAcrossServers tool
+			result, err := tools.FindReferencesAcrossServers(ctx, []*lsp.Client{suite.Client}, tc.symbolName)
 			if err != nil {
 				t.Fatalf("Failed to find references: %v", err)
 			}
@@ -117,6 +119,95 @@ func TestFindReferences(t *testing.T) {
 	}
 }
 
+// TestFindReferencesAt tests finding references by position, which works for
+// locals and fields that aren't workspace symbols
+func TestFindReferencesAt(t *testing.T) {
+	suite := internal.GetTestSuite(t)
+
+	ctx, cancel := context.WithTimeout(suite.Context, 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name               string
+		file               string
+		line               int
+		column             int
+		includeDeclaration bool
+		expectedLocations  []string // Locations that should be in the result
+		declaration        string   // Location of the declaration
+		expectedFiles      int
+		snapshotName       string
+	}{
+		{
+			name:              "Local variable",
+			file:              "consumer.go",
+			line:              7,
+			column:            2,
+			expectedLocations: []string{"L8:C14"},
+			declaration:       "L7:C2",
+			expectedFiles:     1,
+			snapshotName:      "local-variable",
+		},
+		{
+			name:               "Local variable with declaration",
+			file:               "consumer.go",
+			line:               7,
+			column:             2,
+			includeDeclaration: true,
+			expectedLocations:  []string{"L7:C2", "L8:C14"},
+			expectedFiles:      1,
+			snapshotName:       "local-variable-declaration",
+		},
+		{
+			name:              "Struct field",
+			file:              "types.go",
+			line:              8,
+			column:            2,
+			expectedLocations: []string{"L15:C11", "L13:C3"},
+			declaration:       "L8:C2",
+			expectedFiles:     3, // types.go, consumer.go and another_consumer.go
+			snapshotName:      "struct-field",
+		},
+		{
+			name:               "Struct field with declaration",
+			file:               "types.go",
+			line:               8,
+			column:             2,
+			includeDeclaration: true,
+			expectedLocations:  []string{"L8:C2", "L15:C11", "L13:C3"},
+			expectedFiles:      3,
+			snapshotName:       "struct-field-declaration",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(suite.WorkspaceDir, tc.file)
+			result, err := tools.FindReferencesAt(ctx, suite.Client, filePath, tc.line, tc.column, tc.includeDeclaration)
+			if err != nil {
+				t.Fatalf("Failed to find references: %v", err)
+			}
+
+			for _, location := range tc.expectedLocations {
+				if !strings.Contains(result, location) {
+					t.Errorf("References do not contain expected location %s: %s", location, result)
+				}
+			}
+
+			// The declaration is only listed when asked for
+			if tc.declaration != "" && strings.Contains(result, tc.declaration) {
+				t.Errorf("References contain the declaration at %s: %s", tc.declaration, result)
+			}
+
+			if fileCount := strings.Count(result, "References in File"); fileCount != tc.expectedFiles {
+				t.Errorf("Expected references in %d files, but found in %d files", tc.expectedFiles, fileCount)
+			}
+
+			common.SnapshotTest(t, "go", "references", tc.snapshotName, result)
+		})
+	}
+}
+
 // countFilesInResult counts the number of unique files mentioned in the result
 func countFilesInResult(result string) int {
 	fileMap := make(map[string]bool)
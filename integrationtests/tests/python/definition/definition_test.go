@@ -8,6 +8,7 @@ import (
 
 	"github.com/isaacphi/mcp-language-server/integrationtests/tests/common"
 	"github.com/isaacphi/mcp-language-server/integrationtests/tests/python/internal"
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
 	"github.com/isaacphi/mcp-language-server/internal/tools"
 )
 
@@ -79,8 +80,8 @@ func TestReadDefinition(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			// Call the ReadDefinition tool
-			result, err := tools.ReadDefinition(ctx, suite.Client, tc.symbolName)
+			// Call the ReadDefinitionAcrossServers tool
+			result, err := tools.ReadDefinitionAcrossServers(ctx, []*lsp.Client{suite.Client}, tc.symbolName)
 			if err != nil {
 				t.Fatalf("Failed to read definition: %v", err)
 			}
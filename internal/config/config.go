@@ -0,0 +1,316 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/logging"
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/watcher"
+)
+
+// DefaultFileName is the configuration file looked for in the workspace when
+// no file is given on the command line
+const DefaultFileName = ".mcp-language-server.json"
+
+// Config is the contents of a configuration file. Everything is optional.
+type Config struct {
+	// Servers are the language servers to run, in routing order
+	Servers []ServerConfig `json:"servers"`
+
+	Watcher WatcherConfig `json:"watcher"`
+	Tools   ToolsConfig   `json:"tools"`
+	Logging LoggingConfig `json:"logging"`
+
+	// SettingsFile is a settings file answering workspace/configuration
+	// requests, relative to the configuration file. It is reloaded when it
+	// changes.
+	SettingsFile string `json:"settingsFile"`
+
+	// RequestTimeout is the maximum time to wait for a response from a
+	// language server, e.g. "30s"
+	RequestTimeout *Duration `json:"requestTimeout"`
+}
+
+// ServerConfig describes a language server and the files it handles
+type ServerConfig struct {
+	// Name identifies the server in logs. Defaults to the command name.
+	Name string `json:"name"`
+
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+
+	// Languages and Globs select the files routed to the server. A server
+	// without either handles every file not claimed by an earlier server.
+	Languages []string `json:"languages"`
+	Globs     []string `json:"globs"`
+
+	// Profile selects how the server is set up, e.g. "rust-analyzer".
+	// Defaults to the profile for the command.
+	Profile string `json:"profile"`
+
+	// InitializationOptions are sent to the server in the initialize request
+	InitializationOptions any `json:"initializationOptions"`
+
+	// Settings are returned to the server's workspace/configuration
+	// requests, keyed by section, e.g. {"gopls": {"staticcheck": true}}
+	Settings map[string]any `json:"settings"`
+}
+
+// WatcherConfig changes the workspace watcher defaults
+type WatcherConfig struct {
+	// ExcludedDirs are directory names to skip in addition to the defaults
+	ExcludedDirs []string `json:"excludedDirs"`
+
+	// ExcludedFileExtensions are extensions to skip in addition to the defaults
+	ExcludedFileExtensions []string `json:"excludedFileExtensions"`
+
+	// DebounceTime is how long to wait before reporting file changes, e.g. "300ms"
+	DebounceTime *Duration `json:"debounceTime"`
+
+	// MaxFileSize is the largest file, in bytes, that is opened in the server
+	MaxFileSize int64 `json:"maxFileSize"`
+}
+
+// ToolsConfig selects which MCP tools are available. At most one of Enabled
+// and Disabled may be set.
+type ToolsConfig struct {
+	// Enabled lists the only tools to register
+	Enabled []string `json:"enabled"`
+
+	// Disabled lists tools not to register
+	Disabled []string `json:"disabled"`
+
+	// FormatAfterEdit formats files with the language server after edit_file
+	// changes them
+	FormatAfterEdit bool `json:"formatAfterEdit"`
+}
+
+// LoggingConfig changes the log levels and destination
+type LoggingConfig struct {
+	// Level is the minimum level for all components, e.g. "info"
+	Level string `json:"level"`
+
+	// Components overrides the level for specific components, e.g. {"wire": "debug"}
+	Components map[string]string `json:"components"`
+
+	// File is a file that logs are written to in addition to stderr
+	File string `json:"file"`
+}
+
+// Duration is a time.Duration written as a string such as "1m30s"
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"500ms\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Find returns the path of the default configuration file in the workspace,
+// or an empty string if there is none
+func Find(workspaceDir string) string {
+	configPath := filepath.Join(workspaceDir, DefaultFileName)
+	if _, err := os.Stat(configPath); err != nil {
+		return ""
+	}
+	return configPath
+}
+
+// Load reads and validates a configuration file
+func Load(configPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", configPath, err)
+	}
+
+	// Paths in the file are relative to it, wherever it is
+	if cfg.SettingsFile != "" && !filepath.IsAbs(cfg.SettingsFile) {
+		dir, err := filepath.Abs(filepath.Dir(configPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve settings file: %w", err)
+		}
+		cfg.SettingsFile = filepath.Join(dir, cfg.SettingsFile)
+	}
+	return cfg, nil
+}
+
+// Parse decodes and validates configuration file contents
+func Parse(data []byte) (*Config, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Catch misspelled settings instead of silently ignoring them
+	decoder.DisallowUnknownFields()
+
+	var cfg Config
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	// The file holds a single value, so anything after it is a mistake
+	if err := decoder.Decode(new(json.RawMessage)); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected content after the configuration")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks the configuration for mistakes and reports all of them
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, v ...any) {
+		errs = append(errs, fmt.Errorf(format, v...))
+	}
+
+	for i, server := range c.Servers {
+		if strings.TrimSpace(server.Command) == "" {
+			fail("servers[%d]: command is required", i)
+		}
+		for _, language := range server.Languages {
+			if strings.TrimSpace(language) == "" {
+				fail("servers[%d]: languages must not be empty", i)
+			}
+		}
+		for _, glob := range server.Globs {
+			if _, err := path.Match(glob, ""); err != nil {
+				fail("servers[%d]: invalid glob %q", i, glob)
+			}
+		}
+		if server.Profile != "" {
+			if _, err := lsp.LookupProfile(server.Profile); err != nil {
+				fail("servers[%d]: %v", i, err)
+			}
+		}
+	}
+
+	if c.Watcher.DebounceTime != nil && c.Watcher.DebounceTime.Duration < 0 {
+		fail("watcher.debounceTime must not be negative")
+	}
+	if c.Watcher.MaxFileSize < 0 {
+		fail("watcher.maxFileSize must not be negative")
+	}
+
+	if len(c.Tools.Enabled) > 0 && len(c.Tools.Disabled) > 0 {
+		fail("tools: only one of enabled and disabled may be set")
+	}
+
+	if c.Logging.Level != "" {
+		if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+			fail("logging.level: %v", err)
+		}
+	}
+	for component, level := range c.Logging.Components {
+		if !isComponent(component) {
+			fail("logging.components: unknown component %q", component)
+		}
+		if _, err := logging.ParseLevel(level); err != nil {
+			fail("logging.components.%s: %v", component, err)
+		}
+	}
+
+	if c.RequestTimeout != nil && c.RequestTimeout.Duration < 0 {
+		fail("requestTimeout must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// ServerName returns the configured name of the server or its command name
+func (s ServerConfig) ServerName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return filepath.Base(s.Command)
+}
+
+// Apply changes a watcher configuration according to the file
+func (w WatcherConfig) Apply(cfg *watcher.WatcherConfig) {
+	for _, dir := range w.ExcludedDirs {
+		cfg.ExcludedDirs[dir] = true
+	}
+	for _, ext := range w.ExcludedFileExtensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		cfg.ExcludedFileExtensions[strings.ToLower(ext)] = true
+	}
+	if w.DebounceTime != nil {
+		cfg.DebounceTime = w.DebounceTime.Duration
+	}
+	if w.MaxFileSize > 0 {
+		cfg.MaxFileSize = w.MaxFileSize
+	}
+}
+
+// IsEnabled reports whether a tool should be registered
+func (t ToolsConfig) IsEnabled(name string) bool {
+	if len(t.Enabled) > 0 {
+		return slices.Contains(t.Enabled, name)
+	}
+	return !slices.Contains(t.Disabled, name)
+}
+
+// Names returns every tool name mentioned in the configuration
+func (t ToolsConfig) Names() []string {
+	return append(slices.Clone(t.Enabled), t.Disabled...)
+}
+
+// Apply sets log levels and the log file. Validate must have succeeded.
+func (l LoggingConfig) Apply() error {
+	if l.Level != "" {
+		level, err := logging.ParseLevel(l.Level)
+		if err != nil {
+			return err
+		}
+		logging.SetGlobalLevel(level)
+	}
+
+	for component, levelName := range l.Components {
+		level, err := logging.ParseLevel(levelName)
+		if err != nil {
+			return err
+		}
+		logging.SetLevel(logging.Component(component), level)
+	}
+
+	if l.File != "" {
+		return logging.SetupFileLogging(l.File)
+	}
+
+	return nil
+}
+
+func isComponent(name string) bool {
+	return slices.Contains(logging.Components, logging.Component(name))
+}
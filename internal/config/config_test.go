@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/watcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(`{
+		"servers": [
+			{
+				"command": "/usr/bin/gopls",
+				"languages": ["go"],
+				"env": {"GOFLAGS": "-tags=integration"},
+				"initializationOptions": {"codelenses": {"test": true}},
+				"settings": {"gopls": {"staticcheck": true}}
+			},
+			{
+				"name": "pyright",
+				"command": "pyright-langserver",
+				"args": ["--stdio"],
+				"globs": ["**/*.py"]
+			}
+		],
+		"watcher": {"excludedDirs": ["tmp"], "debounceTime": "1s", "maxFileSize": 1024},
+		"tools": {"disabled": ["rename_symbol"], "formatAfterEdit": true},
+		"logging": {"level": "debug", "components": {"wire": "error"}},
+		"requestTimeout": "45s"
+	}`))
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Servers, 2)
+	assert.Equal(t, "gopls", cfg.Servers[0].ServerName())
+	assert.Equal(t, "-tags=integration", cfg.Servers[0].Env["GOFLAGS"])
+	assert.Equal(t, map[string]any{"staticcheck": true}, cfg.Servers[0].Settings["gopls"])
+	assert.NotNil(t, cfg.Servers[0].InitializationOptions)
+	assert.Equal(t, "pyright", cfg.Servers[1].ServerName())
+	assert.Equal(t, []string{"--stdio"}, cfg.Servers[1].Args)
+
+	assert.Equal(t, time.Second, cfg.Watcher.DebounceTime.Duration)
+	assert.Equal(t, 45*time.Second, cfg.RequestTimeout.Duration)
+	assert.False(t, cfg.Tools.IsEnabled("rename_symbol"))
+	assert.True(t, cfg.Tools.IsEnabled("hover"))
+	assert.True(t, cfg.Tools.FormatAfterEdit)
+}
+
+func TestParseEmpty(t *testing.T) {
+	cfg, err := Parse([]byte(``))
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Servers)
+
+	cfg, err = Parse([]byte(`{}`))
+	require.NoError(t, err)
+	assert.Nil(t, cfg.RequestTimeout)
+}
+
+func TestParseTrailingContent(t *testing.T) {
+	for _, data := range []string{`{} garbage`, `{}}`, `{} {}`} {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+
+	_, err := Parse([]byte("{}\n"))
+	assert.NoError(t, err)
+}
+
+func TestParseUnknownField(t *testing.T) {
+	_, err := Parse([]byte(`{"servers": [{"command": "gopls", "language": ["go"]}]}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "language")
+}
+
+func TestParseInvalidDuration(t *testing.T) {
+	_, err := Parse([]byte(`{"requestTimeout": 30}`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`{"requestTimeout": "soon"}`))
+	assert.Error(t, err)
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	_, err := Parse([]byte(`{
+		"servers": [{"languages": ["go"]}, {"command": "pyright", "globs": ["[*.py"], "profile": "pylance"}],
+		"watcher": {"maxFileSize": -1},
+		"tools": {"enabled": ["hover"], "disabled": ["definition"]},
+		"logging": {"level": "loud", "components": {"parser": "info"}}
+	}`))
+	require.Error(t, err)
+
+	for _, want := range []string{
+		"servers[0]: command is required",
+		`servers[1]: invalid glob "[*.py"`,
+		`servers[1]: unknown profile "pylance"`,
+		"watcher.maxFileSize must not be negative",
+		"tools: only one of enabled and disabled may be set",
+		"logging.level",
+		`logging.components: unknown component "parser"`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestFindAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	assert.Empty(t, Find(dir))
+
+	configPath := filepath.Join(dir, DefaultFileName)
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"tools": {"enabled": ["hover"]}}`), 0644))
+	assert.Equal(t, configPath, Find(dir))
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+	assert.True(t, cfg.Tools.IsEnabled("hover"))
+	assert.False(t, cfg.Tools.IsEnabled("definition"))
+
+	// The settings file is relative to the configuration file
+	otherDir := t.TempDir()
+	otherPath := filepath.Join(otherDir, "config.json")
+	require.NoError(t, os.WriteFile(otherPath, []byte(`{"settingsFile": "settings.json"}`), 0644))
+	cfg, err = Load(otherPath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(otherDir, "settings.json"), cfg.SettingsFile)
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"servers": [{}]}`), 0644))
+	_, err = Load(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), configPath)
+}
+
+func TestWatcherConfigApply(t *testing.T) {
+	cfg := watcher.DefaultWatcherConfig()
+	WatcherConfig{
+		ExcludedDirs:           []string{"tmp"},
+		ExcludedFileExtensions: []string{"LOG", ".bak"},
+		DebounceTime:           &Duration{time.Second},
+		MaxFileSize:            2048,
+	}.Apply(cfg)
+
+	assert.True(t, cfg.ExcludedDirs["tmp"])
+	assert.True(t, cfg.ExcludedDirs[".git"], "defaults are kept")
+	assert.True(t, cfg.ExcludedFileExtensions[".log"])
+	assert.True(t, cfg.ExcludedFileExtensions[".bak"])
+	assert.Equal(t, time.Second, cfg.DebounceTime)
+	assert.Equal(t, int64(2048), cfg.MaxFileSize)
+
+	// Unset values leave the defaults alone
+	defaults := watcher.DefaultWatcherConfig()
+	cfg = watcher.DefaultWatcherConfig()
+	WatcherConfig{}.Apply(cfg)
+	assert.Equal(t, defaults.DebounceTime, cfg.DebounceTime)
+	assert.Equal(t, defaults.MaxFileSize, cfg.MaxFileSize)
+}
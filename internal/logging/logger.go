@@ -43,6 +43,24 @@ func (l LogLevel) String() string {
 	}
 }
 
+// ParseLevel converts a level name such as "debug" or "WARN" to a LogLevel
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "DEBUG":
+		return LevelDebug, nil
+	case "INFO":
+		return LevelInfo, nil
+	case "WARN":
+		return LevelWarn, nil
+	case "ERROR":
+		return LevelError, nil
+	case "FATAL":
+		return LevelFatal, nil
+	default:
+		return 0, fmt.Errorf("unknown log level: %s", name)
+	}
+}
+
 // Component represents a specific part of the application for which logs can be filtered
 type Component string
 
@@ -88,17 +106,8 @@ func init() {
 
 	// Parse log level from environment variable
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
-		switch strings.ToUpper(level) {
-		case "DEBUG":
-			DefaultMinLevel = LevelDebug
-		case "INFO":
-			DefaultMinLevel = LevelInfo
-		case "WARN":
-			DefaultMinLevel = LevelWarn
-		case "ERROR":
-			DefaultMinLevel = LevelError
-		case "FATAL":
-			DefaultMinLevel = LevelFatal
+		if parsed, err := ParseLevel(level); err == nil {
+			DefaultMinLevel = parsed
 		}
 
 		// Set all components to this level by default
@@ -116,21 +125,8 @@ func init() {
 			}
 
 			comp := Component(strings.TrimSpace(compAndLevel[0]))
-			levelStr := strings.ToUpper(strings.TrimSpace(compAndLevel[1]))
-
-			var level LogLevel
-			switch levelStr {
-			case "DEBUG":
-				level = LevelDebug
-			case "INFO":
-				level = LevelInfo
-			case "WARN":
-				level = LevelWarn
-			case "ERROR":
-				level = LevelError
-			case "FATAL":
-				level = LevelFatal
-			default:
+			level, err := ParseLevel(compAndLevel[1])
+			if err != nil {
 				continue
 			}
 
@@ -151,6 +147,9 @@ func init() {
 	log.SetFlags(log.Ldate | log.Ltime | log.Lmicroseconds)
 }
 
+// Components lists every component that can be configured
+var Components = []Component{Core, LSP, LSPWire, LSPProcess, Watcher, Tools}
+
 // Logger is the interface for component-specific logging
 type Logger interface {
 	Debug(format string, v ...any)
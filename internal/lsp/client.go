@@ -16,15 +16,64 @@ import (
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
+// ClientConfig holds optional settings for a language server
+type ClientConfig struct {
+	// Env is added to the environment the server process inherits
+	Env map[string]string
+
+	// Profile describes the server. It is chosen from the command if nil.
+	Profile *Profile
+
+	// InitializationOptions are sent in the initialize request. The
+	// profile's options are used if nil.
+	InitializationOptions any
+
+	// Settings answer workspace/configuration requests, keyed by section.
+	// Settings set on the client later are layered over them.
+	Settings map[string]any
+}
+
+// DefaultClientConfig returns a configuration with sensible defaults
+func DefaultClientConfig() *ClientConfig {
+	return &ClientConfig{}
+}
+
 type Client struct {
 	Cmd    *exec.Cmd
 	stdin  io.WriteCloser
 	stdout *bufio.Reader
 	stderr io.ReadCloser
 
+	// Command and arguments used to (re)start the server process
+	command string
+	args    []string
+	config  *ClientConfig
+	profile *Profile
+
+	// Answers workspace/configuration requests
+	settings *SettingsStore
+
+	// Guards the process and its pipes, which are replaced on restart
+	procMu sync.RWMutex
+
+	// Closed when the current server process stops producing messages
+	exited  chan struct{}
+	exitErr error
+
+	// Set once Close is called so an intentional shutdown isn't treated as a crash
+	closing atomic.Bool
+
 	// Request ID counter
 	nextID atomic.Int32
 
+	// Serializes writes to stdin
+	writeMu sync.Mutex
+
+	// Request timeouts. A zero duration disables the timeout.
+	defaultTimeout time.Duration
+	methodTimeouts map[string]time.Duration
+	timeoutsMu     sync.RWMutex
+
 	// Response handlers
 	handlers   map[string]chan *Message
 	handlersMu sync.RWMutex
@@ -38,51 +87,145 @@ type Client struct {
 	notificationMu       sync.RWMutex
 
 	// Diagnostic cache
-	diagnostics   map[protocol.DocumentUri][]protocol.Diagnostic
-	diagnosticsMu sync.RWMutex
+	diagnostics        map[protocol.DocumentUri]*fileDiagnostics
+	diagnosticsSeq     uint64
+	diagnosticsChanged chan struct{}
+	diagnosticsMu      sync.RWMutex
+
+	// Capabilities reported by the server when it was initialized
+	capabilities             protocol.ServerCapabilities
+	pullDiagnostics          bool
+	pullWorkspaceDiagnostics bool
+	capabilitiesMu           sync.RWMutex
 
 	// Files are currently opened by the LSP
 	openFiles   map[string]*OpenFileInfo
 	openFilesMu sync.RWMutex
+
+	// Work done progress reported by the server, by token
+	progress          map[string]*ProgressInfo
+	progressEnded     map[string]bool
+	progressChanged   chan struct{}
+	progressChangedAt time.Time
+	ready             bool
+	readiness         *ReadinessConfig
+	progressMu        sync.RWMutex
+
+	// Distinguishes this client's file watcher registrations from those of
+	// other clients sharing the workspace watcher
+	instance int32
+
+	// File watcher registrations received from the server, by registration ID
+	watchRegistrations   map[string][]protocol.FileSystemWatcher
+	watchRegistrationsMu sync.RWMutex
 }
 
+// clientInstances counts the clients created by this process
+var clientInstances atomic.Int32
+
+// initializationOptions returns the configured initialization options or
+// the profile's
+func (c *Client) initializationOptions() any {
+	if c.config.InitializationOptions != nil {
+		return c.config.InitializationOptions
+	}
+	return c.Profile().InitializationOptions
+}
+
+// NewClient starts a language server with default configuration
 func NewClient(command string, args ...string) (*Client, error) {
-	cmd := exec.Command(command, args...)
+	return NewClientWithConfig(DefaultClientConfig(), command, args...)
+}
+
+// NewClientWithConfig starts a language server with custom configuration
+func NewClientWithConfig(config *ClientConfig, command string, args ...string) (*Client, error) {
+	if config == nil {
+		config = DefaultClientConfig()
+	}
+	profile := config.Profile
+	if profile == nil {
+		profile = ProfileForCommand(command)
+	}
+	lspLogger.Info("Using %s profile for %s", profile.Name, command)
+	for _, quirk := range profile.Quirks {
+		lspLogger.Info("Known %s quirk: %s", profile.Name, quirk)
+	}
+
+	readiness := DefaultReadinessConfig()
+	if profile.Readiness != nil {
+		readiness = profile.Readiness
+	}
+	client := &Client{
+		instance:              clientInstances.Add(1),
+		command:               command,
+		args:                  args,
+		config:                config,
+		profile:               profile,
+		settings:              NewSettingsStore(config.Settings),
+		handlers:              make(map[string]chan *Message),
+		notificationHandlers:  make(map[string]NotificationHandler),
+		serverRequestHandlers: make(map[string]ServerRequestHandler),
+		diagnostics:           make(map[protocol.DocumentUri]*fileDiagnostics),
+		diagnosticsChanged:    make(chan struct{}),
+		openFiles:             make(map[string]*OpenFileInfo),
+		watchRegistrations:    make(map[string][]protocol.FileSystemWatcher),
+		progress:              make(map[string]*ProgressInfo),
+		progressEnded:         make(map[string]bool),
+		progressChanged:       make(chan struct{}),
+		progressChangedAt:     time.Now(),
+		readiness:             readiness,
+		defaultTimeout:        DefaultRequestTimeout,
+		methodTimeouts:        make(map[string]time.Duration),
+	}
+
+	if err := client.start(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// start launches the server process and begins reading its output
+func (c *Client) start() error {
+	cmd := exec.Command(c.command, c.args...)
 	// Copy env
 	cmd.Env = os.Environ()
+	for name, value := range c.config.Env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+		return fmt.Errorf("failed to create stdin pipe: %w", err)
 	}
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
 
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
-	}
-
-	client := &Client{
-		Cmd:                   cmd,
-		stdin:                 stdin,
-		stdout:                bufio.NewReader(stdout),
-		stderr:                stderr,
-		handlers:              make(map[string]chan *Message),
-		notificationHandlers:  make(map[string]NotificationHandler),
-		serverRequestHandlers: make(map[string]ServerRequestHandler),
-		diagnostics:           make(map[protocol.DocumentUri][]protocol.Diagnostic),
-		openFiles:             make(map[string]*OpenFileInfo),
+		return fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
 	// Start the LSP server process
 	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start LSP server: %w", err)
+		return fmt.Errorf("failed to start LSP server: %w", err)
 	}
 
+	reader := bufio.NewReader(stdout)
+	exited := make(chan struct{})
+
+	c.procMu.Lock()
+	c.Cmd = cmd
+	c.stdin = stdin
+	c.stdout = reader
+	c.stderr = stderr
+	c.exited = exited
+	c.exitErr = nil
+	c.procMu.Unlock()
+
 	// Handle stderr in a separate goroutine with proper logging
 	go func() {
 		scanner := bufio.NewScanner(stderr)
@@ -96,9 +239,80 @@ func NewClient(command string, args ...string) (*Client, error) {
 	}()
 
 	// Start message handling loop
-	go client.handleMessages()
+	go c.handleMessages(reader, exited)
 
-	return client, nil
+	return nil
+}
+
+// restart kills the current server process, if it is still running, and
+// starts a new one. The new server still needs to be initialized.
+func (c *Client) restart() error {
+	c.procMu.RLock()
+	cmd := c.Cmd
+	stdin := c.stdin
+	c.procMu.RUnlock()
+
+	if stdin != nil {
+		_ = stdin.Close()
+	}
+	if cmd != nil && cmd.Process != nil {
+		_ = cmd.Process.Kill()
+		// Reap the old process; the error only reports how it died
+		_ = cmd.Wait()
+	}
+
+	c.resetProgress()
+	return c.start()
+}
+
+// Exited returns a channel that is closed when the current server process
+// stops responding, either because it exited or its output became unreadable
+func (c *Client) Exited() <-chan struct{} {
+	c.procMu.RLock()
+	defer c.procMu.RUnlock()
+	return c.exited
+}
+
+// ExitError returns the error that ended the current server process, if any
+func (c *Client) ExitError() error {
+	c.procMu.RLock()
+	defer c.procMu.RUnlock()
+	return c.exitErr
+}
+
+// IsClosing reports whether Close has been called on the client
+func (c *Client) IsClosing() bool {
+	return c.closing.Load()
+}
+
+// DefaultRequestTimeout is how long a request may wait for a response unless
+// a different timeout is configured for the client or the method
+const DefaultRequestTimeout = 60 * time.Second
+
+// SetRequestTimeout sets the timeout used for requests that have no method
+// specific timeout. A zero duration disables the timeout.
+func (c *Client) SetRequestTimeout(timeout time.Duration) {
+	c.timeoutsMu.Lock()
+	defer c.timeoutsMu.Unlock()
+	c.defaultTimeout = timeout
+}
+
+// SetMethodTimeout overrides the request timeout for a single LSP method,
+// e.g. "workspace/symbol". A zero duration disables the timeout for it.
+func (c *Client) SetMethodTimeout(method string, timeout time.Duration) {
+	c.timeoutsMu.Lock()
+	defer c.timeoutsMu.Unlock()
+	c.methodTimeouts[method] = timeout
+}
+
+// requestTimeout returns the timeout that applies to a request for method
+func (c *Client) requestTimeout(method string) time.Duration {
+	c.timeoutsMu.RLock()
+	defer c.timeoutsMu.RUnlock()
+	if timeout, ok := c.methodTimeouts[method]; ok {
+		return timeout
+	}
+	return c.defaultTimeout
 }
 
 func (c *Client) RegisterNotificationHandler(method string, handler NotificationHandler) {
@@ -134,6 +348,15 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 			RootURI:  protocol.DocumentUri("file://" + workspaceDir),
 			Capabilities: protocol.ClientCapabilities{
 				Workspace: protocol.WorkspaceClientCapabilities{
+					ApplyEdit: true,
+					WorkspaceEdit: &protocol.WorkspaceEditClientCapabilities{
+						DocumentChanges: true,
+						ResourceOperations: []protocol.ResourceOperationKind{
+							protocol.Create,
+							protocol.Rename,
+							protocol.Delete,
+						},
+					},
 					Configuration: true,
 					DidChangeConfiguration: protocol.DidChangeConfigurationClientCapabilities{
 						DynamicRegistration: true,
@@ -142,6 +365,11 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 						DynamicRegistration:    true,
 						RelativePatternSupport: true,
 					},
+					Symbol: &protocol.WorkspaceSymbolClientCapabilities{
+						ResolveSupport: &protocol.ClientSymbolResolveOptions{
+							Properties: []string{"location.range"},
+						},
+					},
 				},
 				TextDocument: protocol.TextDocumentClientCapabilities{
 					Synchronization: &protocol.TextDocumentSyncClientCapabilities{
@@ -149,45 +377,128 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 						DidSave:             true,
 					},
 					Completion: protocol.CompletionClientCapabilities{
-						CompletionItem: protocol.ClientCompletionItemOptions{},
+						CompletionItem: protocol.ClientCompletionItemOptions{
+							DocumentationFormat: []protocol.MarkupKind{protocol.Markdown, protocol.PlainText},
+							DeprecatedSupport:   true,
+							PreselectSupport:    true,
+							TagSupport: &protocol.CompletionItemTagOptions{
+								ValueSet: []protocol.CompletionItemTag{protocol.ComplDeprecated},
+							},
+							ResolveSupport: &protocol.ClientCompletionItemResolveOptions{
+								Properties: []string{"documentation", "detail"},
+							},
+							LabelDetailsSupport: true,
+						},
+						ContextSupport: true,
+					},
+					SignatureHelp: &protocol.SignatureHelpClientCapabilities{
+						SignatureInformation: &protocol.ClientSignatureInformationOptions{
+							DocumentationFormat:    []protocol.MarkupKind{protocol.Markdown, protocol.PlainText},
+							ActiveParameterSupport: true,
+						},
+						ContextSupport: true,
 					},
 					CodeLens: &protocol.CodeLensClientCapabilities{
 						DynamicRegistration: true,
 					},
-					DocumentSymbol: protocol.DocumentSymbolClientCapabilities{},
+					DocumentSymbol: protocol.DocumentSymbolClientCapabilities{
+						HierarchicalDocumentSymbolSupport: true,
+					},
+					Definition: &protocol.DefinitionClientCapabilities{
+						LinkSupport: true,
+					},
+					TypeDefinition: &protocol.TypeDefinitionClientCapabilities{
+						LinkSupport: true,
+					},
+					Declaration: &protocol.DeclarationClientCapabilities{
+						LinkSupport: true,
+					},
+					Implementation: &protocol.ImplementationClientCapabilities{
+						LinkSupport: true,
+					},
+					TypeHierarchy:     &protocol.TypeHierarchyClientCapabilities{},
+					InlayHint:         &protocol.InlayHintClientCapabilities{},
+					DocumentHighlight: &protocol.DocumentHighlightClientCapabilities{},
 					CodeAction: protocol.CodeActionClientCapabilities{
 						CodeActionLiteralSupport: protocol.ClientCodeActionLiteralOptions{
 							CodeActionKind: protocol.ClientCodeActionKindOptions{
-								ValueSet: []protocol.CodeActionKind{},
+								ValueSet: []protocol.CodeActionKind{
+									protocol.Empty,
+									protocol.QuickFix,
+									protocol.Refactor,
+									protocol.RefactorExtract,
+									protocol.RefactorInline,
+									protocol.RefactorMove,
+									protocol.RefactorRewrite,
+									protocol.Source,
+									protocol.SourceOrganizeImports,
+									protocol.SourceFixAll,
+								},
 							},
 						},
+						IsPreferredSupport: true,
+						DisabledSupport:    true,
+						DataSupport:        true,
+						ResolveSupport: &protocol.ClientCodeActionResolveOptions{
+							Properties: []string{"edit"},
+						},
 					},
+					Formatting:      &protocol.DocumentFormattingClientCapabilities{},
+					RangeFormatting: &protocol.DocumentRangeFormattingClientCapabilities{},
 					PublishDiagnostics: protocol.PublishDiagnosticsClientCapabilities{
 						VersionSupport: true,
 					},
 					SemanticTokens: protocol.SemanticTokensClientCapabilities{
 						Requests: protocol.ClientSemanticTokensRequestOptions{
-							Range: &protocol.Or_ClientSemanticTokensRequestOptions_range{},
-							Full:  &protocol.Or_ClientSemanticTokensRequestOptions_full{},
+							Range: &protocol.Or_ClientSemanticTokensRequestOptions_range{Value: true},
+							Full:  &protocol.Or_ClientSemanticTokensRequestOptions_full{Value: true},
+						},
+						TokenTypes: []string{
+							string(protocol.NamespaceType),
+							string(protocol.TypeType),
+							string(protocol.ClassType),
+							string(protocol.EnumType),
+							string(protocol.InterfaceType),
+							string(protocol.StructType),
+							string(protocol.TypeParameterType),
+							string(protocol.ParameterType),
+							string(protocol.VariableType),
+							string(protocol.PropertyType),
+							string(protocol.EnumMemberType),
+							string(protocol.EventType),
+							string(protocol.FunctionType),
+							string(protocol.MethodType),
+							string(protocol.MacroType),
+							string(protocol.KeywordType),
+							string(protocol.ModifierType),
+							string(protocol.CommentType),
+							string(protocol.StringType),
+							string(protocol.NumberType),
+							string(protocol.RegexpType),
+							string(protocol.OperatorType),
+							string(protocol.DecoratorType),
+							string(protocol.LabelType),
 						},
-						TokenTypes:     []string{},
-						TokenModifiers: []string{},
-						Formats:        []protocol.TokenFormat{},
+						TokenModifiers: []string{
+							string(protocol.ModDeclaration),
+							string(protocol.ModDefinition),
+							string(protocol.ModReadonly),
+							string(protocol.ModStatic),
+							string(protocol.ModDeprecated),
+							string(protocol.ModAbstract),
+							string(protocol.ModAsync),
+							string(protocol.ModModification),
+							string(protocol.ModDocumentation),
+							string(protocol.ModDefaultLibrary),
+						},
+						Formats: []protocol.TokenFormat{protocol.Relative},
 					},
 				},
-				Window: protocol.WindowClientCapabilities{},
-			},
-			InitializationOptions: map[string]any{
-				"codelenses": map[string]bool{
-					"generate":           true,
-					"regenerate_cgo":     true,
-					"test":               true,
-					"tidy":               true,
-					"upgrade_dependency": true,
-					"vendor":             true,
-					"vulncheck":          false,
+				Window: protocol.WindowClientCapabilities{
+					WorkDoneProgress: true,
 				},
 			},
+			InitializationOptions: c.initializationOptions(),
 		},
 	}
 
@@ -196,15 +507,26 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 		return nil, fmt.Errorf("initialize failed: %w", err)
 	}
 
+	c.capabilitiesMu.Lock()
+	c.capabilities = result.Capabilities
+	c.pullDiagnostics = false
+	c.pullWorkspaceDiagnostics = false
+	c.capabilitiesMu.Unlock()
+
 	if err := c.Notify(ctx, "initialized", struct{}{}); err != nil {
 		return nil, fmt.Errorf("initialized notification failed: %w", err)
 	}
 
 	// Register handlers
 	c.RegisterServerRequestHandler("workspace/applyEdit", HandleApplyEdit)
-	c.RegisterServerRequestHandler("workspace/configuration", HandleWorkspaceConfiguration)
-	c.RegisterServerRequestHandler("client/registerCapability", HandleRegisterCapability)
+	c.RegisterServerRequestHandler("workspace/configuration",
+		func(params json.RawMessage) (any, error) { return HandleWorkspaceConfiguration(c, params) })
+	c.RegisterServerRequestHandler("client/registerCapability",
+		func(params json.RawMessage) (any, error) { return HandleRegisterCapability(c, params) })
+	c.RegisterServerRequestHandler("window/workDoneProgress/create", HandleWorkDoneProgressCreate)
 	c.RegisterNotificationHandler("window/showMessage", HandleServerMessage)
+	c.RegisterNotificationHandler("$/progress",
+		func(params json.RawMessage) { HandleProgress(c, params) })
 	c.RegisterNotificationHandler("textDocument/publishDiagnostics",
 		func(params json.RawMessage) { HandleDiagnostics(c, params) })
 
@@ -214,20 +536,30 @@ func (c *Client) InitializeLSPClient(ctx context.Context, workspaceDir string) (
 		return nil, fmt.Errorf("initialization failed: %w", err)
 	}
 
-	// LSP sepecific Initialization
-	path := strings.ToLower(c.Cmd.Path)
-	switch {
-	case strings.Contains(path, "typescript-language-server"):
-		err := initializeTypescriptLanguageServer(ctx, c, workspaceDir)
-		if err != nil {
-			return nil, err
-		}
+	// LSP specific initialization
+	if err := c.preopenFiles(ctx, workspaceDir); err != nil {
+		return nil, fmt.Errorf("failed to open files: %w", err)
 	}
 
 	return &result, nil
 }
 
+// SupportsCompletionResolve reports whether the server fills in completion
+// item details on completionItem/resolve requests
+func (c *Client) SupportsCompletionResolve() bool {
+	c.capabilitiesMu.RLock()
+	defer c.capabilitiesMu.RUnlock()
+	return c.capabilities.CompletionProvider != nil && c.capabilities.CompletionProvider.ResolveProvider
+}
+
 func (c *Client) Close() error {
+	c.closing.Store(true)
+
+	c.procMu.RLock()
+	cmd := c.Cmd
+	stdin := c.stdin
+	c.procMu.RUnlock()
+
 	// Try to close all open files first
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -241,8 +573,8 @@ func (c *Client) Close() error {
 		select {
 		case <-time.After(2 * time.Second):
 			lspLogger.Warn("LSP process did not exit within timeout, forcing kill")
-			if c.Cmd.Process != nil {
-				if err := c.Cmd.Process.Kill(); err != nil {
+			if cmd.Process != nil {
+				if err := cmd.Process.Kill(); err != nil {
 					lspLogger.Error("Failed to kill process: %v", err)
 				} else {
 					lspLogger.Info("Process killed successfully")
@@ -256,34 +588,23 @@ func (c *Client) Close() error {
 	}()
 
 	// Close stdin to signal the server
-	if err := c.stdin.Close(); err != nil {
+	if err := stdin.Close(); err != nil {
 		lspLogger.Error("Failed to close stdin: %v", err)
 	}
 
 	// Wait for process to exit
-	err := c.Cmd.Wait()
+	err := cmd.Wait()
 	close(forcedKill) // Stop the force kill goroutine
 
 	return err
 }
 
-type ServerState int
-
-const (
-	StateStarting ServerState = iota
-	StateReady
-	StateError
-)
-
-func (c *Client) WaitForServerReady(ctx context.Context) error {
-	// TODO: wait for specific messages or poll workspace/symbol
-	time.Sleep(time.Second * 1)
-	return nil
-}
-
 type OpenFileInfo struct {
 	Version int32
 	URI     protocol.DocumentUri
+
+	// Diagnostics sequence number when this version was sent to the server
+	diagnosticsSeq uint64
 }
 
 func (c *Client) OpenFile(ctx context.Context, filepath string) error {
@@ -311,14 +632,16 @@ func (c *Client) OpenFile(ctx context.Context, filepath string) error {
 		},
 	}
 
+	seq := c.currentDiagnosticsSeq()
 	if err := c.Notify(ctx, "textDocument/didOpen", params); err != nil {
 		return err
 	}
 
 	c.openFilesMu.Lock()
 	c.openFiles[uri] = &OpenFileInfo{
-		Version: 1,
-		URI:     protocol.DocumentUri(uri),
+		Version:        1,
+		URI:            protocol.DocumentUri(uri),
+		diagnosticsSeq: seq,
 	}
 	c.openFilesMu.Unlock()
 
@@ -345,6 +668,7 @@ func (c *Client) NotifyChange(ctx context.Context, filepath string) error {
 	// Increment version
 	fileInfo.Version++
 	version := fileInfo.Version
+	fileInfo.diagnosticsSeq = c.currentDiagnosticsSeq()
 	c.openFilesMu.Unlock()
 
 	params := protocol.DidChangeTextDocumentParams{
@@ -425,9 +749,44 @@ func (c *Client) CloseAllFiles(ctx context.Context) {
 	lspLogger.Debug("Closed %d files", len(filesToClose))
 }
 
-func (c *Client) GetFileDiagnostics(uri protocol.DocumentUri) []protocol.Diagnostic {
-	c.diagnosticsMu.RLock()
-	defer c.diagnosticsMu.RUnlock()
+// resetOpenFiles forgets every open file and returns their paths. Used when
+// the server is restarted and the files need to be opened again.
+func (c *Client) resetOpenFiles() []string {
+	c.openFilesMu.Lock()
+	defer c.openFilesMu.Unlock()
+
+	paths := make([]string, 0, len(c.openFiles))
+	for uri := range c.openFiles {
+		paths = append(paths, strings.TrimPrefix(uri, "file://"))
+	}
+	c.openFiles = make(map[string]*OpenFileInfo)
+	return paths
+}
+
+// recordWatchRegistration remembers file watchers registered by the server
+// so they can be replayed after a restart
+func (c *Client) recordWatchRegistration(id string, watchers []protocol.FileSystemWatcher) {
+	c.watchRegistrationsMu.Lock()
+	defer c.watchRegistrationsMu.Unlock()
+	c.watchRegistrations[id] = watchers
+}
+
+// watchRegistrationKey makes a server's registration ID unique among all the
+// clients sharing the file watch handler
+func (c *Client) watchRegistrationKey(id string) string {
+	return fmt.Sprintf("%d/%s", c.instance, id)
+}
 
-	return c.diagnostics[uri]
+// replayWatchRegistrations passes every recorded file watcher registration to
+// the registered file watch handler again
+func (c *Client) replayWatchRegistrations() {
+	c.watchRegistrationsMu.RLock()
+	defer c.watchRegistrationsMu.RUnlock()
+
+	if fileWatchHandler == nil {
+		return
+	}
+	for id, watchers := range c.watchRegistrations {
+		fileWatchHandler(c.watchRegistrationKey(id), watchers)
+	}
 }
@@ -0,0 +1,234 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// fileDiagnostics is the cached diagnostics for a single document
+type fileDiagnostics struct {
+	items []protocol.Diagnostic
+
+	// Document version the diagnostics were computed for, zero if the server
+	// did not say
+	version int32
+
+	// Value of the client's diagnostics sequence when these were stored
+	seq uint64
+
+	// Result ID of the last pull diagnostics report, sent back on the next pull
+	resultID string
+}
+
+// GetFileDiagnostics returns the cached diagnostics for a document
+func (c *Client) GetFileDiagnostics(uri protocol.DocumentUri) []protocol.Diagnostic {
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+
+	if entry, ok := c.diagnostics[uri]; ok {
+		return entry.items
+	}
+	return nil
+}
+
+// GetAllDiagnostics returns the cached diagnostics for every document the
+// server has reported on, including documents without any diagnostics
+func (c *Client) GetAllDiagnostics() map[protocol.DocumentUri][]protocol.Diagnostic {
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+
+	all := make(map[protocol.DocumentUri][]protocol.Diagnostic, len(c.diagnostics))
+	for uri, entry := range c.diagnostics {
+		all[uri] = entry.items
+	}
+	return all
+}
+
+// DiagnosticsChanged returns a channel that is closed the next time
+// diagnostics are stored for any document
+func (c *Client) DiagnosticsChanged() <-chan struct{} {
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+	return c.diagnosticsChanged
+}
+
+// WaitForDiagnostics blocks until the server has reported diagnostics for
+// version of the document, or for a later version, and returns them.
+// Diagnostics published without a version are accepted if they arrived after
+// the document was last sent to the server.
+func (c *Client) WaitForDiagnostics(ctx context.Context, uri protocol.DocumentUri, version int32) ([]protocol.Diagnostic, error) {
+	// Unversioned diagnostics only count if they arrive after this point
+	var sentSeq uint64
+	c.openFilesMu.RLock()
+	if info, ok := c.openFiles[string(uri)]; ok && info.Version == version {
+		sentSeq = info.diagnosticsSeq
+	}
+	c.openFilesMu.RUnlock()
+
+	for {
+		c.diagnosticsMu.RLock()
+		entry, ok := c.diagnostics[uri]
+		changed := c.diagnosticsChanged
+		c.diagnosticsMu.RUnlock()
+
+		if ok {
+			if entry.version != 0 && entry.version >= version {
+				return entry.items, nil
+			}
+			if entry.version == 0 && entry.seq > sentSeq {
+				return entry.items, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// SupportsPullDiagnostics reports whether the server answers
+// textDocument/diagnostic requests
+func (c *Client) SupportsPullDiagnostics() bool {
+	c.capabilitiesMu.RLock()
+	defer c.capabilitiesMu.RUnlock()
+	return c.capabilities.DiagnosticProvider != nil || c.pullDiagnostics
+}
+
+// PullDiagnostics requests diagnostics for a document from the server,
+// stores them in the cache and returns them
+func (c *Client) PullDiagnostics(ctx context.Context, uri protocol.DocumentUri) ([]protocol.Diagnostic, error) {
+	version, _ := c.FileVersion(uri)
+
+	c.diagnosticsMu.RLock()
+	var previousResultID string
+	if entry, ok := c.diagnostics[uri]; ok {
+		previousResultID = entry.resultID
+	}
+	c.diagnosticsMu.RUnlock()
+
+	report, err := c.Diagnostic(ctx, protocol.DocumentDiagnosticParams{
+		TextDocument:     protocol.TextDocumentIdentifier{URI: uri},
+		PreviousResultID: previousResultID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch r := report.Value.(type) {
+	case protocol.RelatedFullDocumentDiagnosticReport:
+		// An unchanged report also decodes as a full report without items
+		if r.Kind == "unchanged" {
+			return c.GetFileDiagnostics(uri), nil
+		}
+		c.storeDiagnostics(uri, version, r.Items, r.ResultID)
+		return r.Items, nil
+	case protocol.RelatedUnchangedDocumentDiagnosticReport:
+		return c.GetFileDiagnostics(uri), nil
+	default:
+		return nil, fmt.Errorf("unexpected diagnostic report: %T", report.Value)
+	}
+}
+
+// SupportsWorkspaceDiagnostics reports whether the server answers
+// workspace/diagnostic requests
+func (c *Client) SupportsWorkspaceDiagnostics() bool {
+	c.capabilitiesMu.RLock()
+	defer c.capabilitiesMu.RUnlock()
+
+	if c.pullWorkspaceDiagnostics {
+		return true
+	}
+	if c.capabilities.DiagnosticProvider == nil {
+		return false
+	}
+	switch opts := c.capabilities.DiagnosticProvider.Value.(type) {
+	case protocol.DiagnosticOptions:
+		return opts.WorkspaceDiagnostics
+	case protocol.DiagnosticRegistrationOptions:
+		return opts.WorkspaceDiagnostics
+	}
+	return false
+}
+
+// PullWorkspaceDiagnostics requests diagnostics for the whole workspace from
+// the server and stores them in the cache. It returns the number of documents
+// the server reported new diagnostics for.
+func (c *Client) PullWorkspaceDiagnostics(ctx context.Context) (int, error) {
+	c.diagnosticsMu.RLock()
+	previous := []protocol.PreviousResultId{}
+	for uri, entry := range c.diagnostics {
+		if entry.resultID != "" {
+			previous = append(previous, protocol.PreviousResultId{URI: uri, Value: entry.resultID})
+		}
+	}
+	c.diagnosticsMu.RUnlock()
+
+	report, err := c.DiagnosticWorkspace(ctx, protocol.WorkspaceDiagnosticParams{
+		PreviousResultIds: previous,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, item := range report.Items {
+		// Unchanged reports also decode as full reports without items
+		full, ok := item.Value.(protocol.WorkspaceFullDocumentDiagnosticReport)
+		if !ok || full.Kind == "unchanged" {
+			continue
+		}
+		c.storeDiagnostics(full.URI, full.Version, full.Items, full.ResultID)
+		updated++
+	}
+	return updated, nil
+}
+
+// FileVersion returns the version of an open document that was last sent to
+// the server
+func (c *Client) FileVersion(uri protocol.DocumentUri) (int32, bool) {
+	c.openFilesMu.RLock()
+	defer c.openFilesMu.RUnlock()
+
+	info, ok := c.openFiles[string(uri)]
+	if !ok {
+		return 0, false
+	}
+	return info.Version, true
+}
+
+// storeDiagnostics replaces the cached diagnostics for a document and wakes
+// up anything waiting for them
+func (c *Client) storeDiagnostics(uri protocol.DocumentUri, version int32, items []protocol.Diagnostic, resultID string) {
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+
+	c.diagnosticsSeq++
+	c.diagnostics[uri] = &fileDiagnostics{
+		items:    items,
+		version:  version,
+		seq:      c.diagnosticsSeq,
+		resultID: resultID,
+	}
+
+	close(c.diagnosticsChanged)
+	c.diagnosticsChanged = make(chan struct{})
+}
+
+// currentDiagnosticsSeq returns the sequence number of the most recently
+// stored diagnostics
+func (c *Client) currentDiagnosticsSeq() uint64 {
+	c.diagnosticsMu.RLock()
+	defer c.diagnosticsMu.RUnlock()
+	return c.diagnosticsSeq
+}
+
+// clearDiagnostics drops all cached diagnostics
+func (c *Client) clearDiagnostics() {
+	c.diagnosticsMu.Lock()
+	defer c.diagnosticsMu.Unlock()
+	c.diagnostics = make(map[protocol.DocumentUri]*fileDiagnostics)
+}
@@ -0,0 +1,113 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publishDiagnostics(client *Client, uri protocol.DocumentUri, version int32, messages ...string) {
+	params := protocol.PublishDiagnosticsParams{URI: uri, Version: version, Diagnostics: []protocol.Diagnostic{}}
+	for _, message := range messages {
+		params.Diagnostics = append(params.Diagnostics, protocol.Diagnostic{Message: message})
+	}
+	raw, _ := json.Marshal(params)
+	HandleDiagnostics(client, raw)
+}
+
+func TestWaitForDiagnosticsVersion(t *testing.T) {
+	client, _, _ := newPipeClient(t)
+	uri := protocol.DocumentUri("file:///tmp/main.go")
+
+	// Diagnostics for an older version don't satisfy the wait
+	publishDiagnostics(client, uri, 1, "stale")
+
+	result := make(chan []protocol.Diagnostic, 1)
+	go func() {
+		diagnostics, err := client.WaitForDiagnostics(context.Background(), uri, 2)
+		assert.NoError(t, err)
+		result <- diagnostics
+	}()
+
+	select {
+	case <-result:
+		t.Fatal("returned diagnostics for an older version")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	publishDiagnostics(client, uri, 2, "current")
+
+	select {
+	case diagnostics := <-result:
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, "current", diagnostics[0].Message)
+	case <-time.After(time.Second):
+		t.Fatal("did not return diagnostics for the current version")
+	}
+}
+
+func TestWaitForDiagnosticsUnversioned(t *testing.T) {
+	client, _, _ := newPipeClient(t)
+	uri := protocol.DocumentUri("file:///tmp/main.py")
+
+	publishDiagnostics(client, uri, 0, "before open")
+	client.openFiles[string(uri)] = &OpenFileInfo{
+		Version:        1,
+		URI:            uri,
+		diagnosticsSeq: client.currentDiagnosticsSeq(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.WaitForDiagnostics(ctx, uri, 1)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected timeout, got %v", err)
+
+	publishDiagnostics(client, uri, 0)
+
+	diagnostics, err := client.WaitForDiagnostics(context.Background(), uri, 1)
+	require.NoError(t, err)
+	assert.Empty(t, diagnostics)
+}
+
+func TestPullDiagnostics(t *testing.T) {
+	client, serverReader, serverWriter := newPipeClient(t)
+	uri := protocol.DocumentUri("file:///tmp/main.rs")
+	client.capabilities.DiagnosticProvider = &protocol.Or_ServerCapabilities_diagnosticProvider{}
+	assert.True(t, client.SupportsPullDiagnostics())
+
+	respond := func(result string) *protocol.DocumentDiagnosticParams {
+		req, err := ReadMessage(serverReader)
+		require.NoError(t, err)
+		assert.Equal(t, "textDocument/diagnostic", req.Method)
+
+		var params protocol.DocumentDiagnosticParams
+		require.NoError(t, json.Unmarshal(req.Params, &params))
+		require.NoError(t, WriteMessage(serverWriter, &Message{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  json.RawMessage(result),
+		}))
+		return &params
+	}
+
+	go respond(`{"kind":"full","resultId":"1","items":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"message":"unused"}]}`)
+	diagnostics, err := client.PullDiagnostics(context.Background(), uri)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, "unused", diagnostics[0].Message)
+
+	// The result ID is sent back, and an unchanged report keeps the cache
+	paramsCh := make(chan *protocol.DocumentDiagnosticParams, 1)
+	go func() { paramsCh <- respond(`{"kind":"unchanged","resultId":"1"}`) }()
+	diagnostics, err = client.PullDiagnostics(context.Background(), uri)
+	require.NoError(t, err)
+	assert.Equal(t, "1", (<-paramsCh).PreviousResultID)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, "unused", client.GetFileDiagnostics(uri)[0].Message)
+}
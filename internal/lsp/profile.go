@@ -0,0 +1,234 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Profile describes what a particular language server needs from the client
+type Profile struct {
+	// Name selects the profile on the command line, e.g. "gopls"
+	Name string
+
+	// Commands are the executable names the profile is chosen for
+	// automatically
+	Commands []string
+
+	// InitializationOptions are sent in the initialize request unless the
+	// client is configured with its own
+	InitializationOptions any
+
+	// PreopenExtensions are file extensions opened in the server after
+	// initialization. Some servers only index files that are open.
+	PreopenExtensions []string
+
+	// Readiness replaces the default readiness configuration if set
+	Readiness *ReadinessConfig
+
+	// SymbolSeparators separate container and member names in qualified
+	// symbol names, e.g. "." in Type.Method or "::" in Type::method
+	SymbolSeparators []string
+
+	// Quirks are known issues with the server, logged when the profile is used
+	Quirks []string
+}
+
+// DefaultProfile is used for servers without a more specific profile
+var DefaultProfile = &Profile{
+	Name:             "default",
+	SymbolSeparators: []string{".", "::"},
+}
+
+var profiles = []*Profile{
+	{
+		Name:     "gopls",
+		Commands: []string{"gopls"},
+		InitializationOptions: map[string]any{
+			"codelenses": map[string]bool{
+				"generate":           true,
+				"regenerate_cgo":     true,
+				"test":               true,
+				"tidy":               true,
+				"upgrade_dependency": true,
+				"vendor":             true,
+				"vulncheck":          false,
+			},
+			// Inlay hints are off unless enabled
+			"hints": map[string]bool{
+				"assignVariableTypes":    true,
+				"compositeLiteralFields": true,
+				"constantValues":         true,
+				"functionTypeParameters": true,
+				"parameterNames":         true,
+				"rangeVariableTypes":     true,
+			},
+			// Semantic token requests fail unless enabled
+			"semanticTokens": true,
+		},
+		SymbolSeparators: []string{"."},
+	},
+	{
+		Name:     "rust-analyzer",
+		Commands: []string{"rust-analyzer"},
+		// Indexing large crate graphs takes a while, and progress reports
+		// pause between phases
+		Readiness: &ReadinessConfig{
+			Timeout:     3 * time.Minute,
+			QuietPeriod: 3 * time.Second,
+			Probe:       true,
+		},
+		SymbolSeparators: []string{"::"},
+		Quirks: []string{
+			"workspace/symbol returns unqualified names; methods are matched by name alone",
+		},
+	},
+	{
+		Name:             "pyright",
+		Commands:         []string{"pyright-langserver", "pyright", "basedpyright-langserver"},
+		SymbolSeparators: []string{"."},
+		Quirks: []string{
+			"analysis settings are only read from workspace/configuration or pyrightconfig.json",
+		},
+	},
+	{
+		Name:              "typescript-language-server",
+		Commands:          []string{"typescript-language-server"},
+		PreopenExtensions: []string{".ts", ".tsx"},
+		// Inlay hints are off unless enabled
+		InitializationOptions: map[string]any{
+			"preferences": map[string]any{
+				"includeInlayParameterNameHints":           "all",
+				"includeInlayVariableTypeHints":            true,
+				"includeInlayPropertyDeclarationTypeHints": true,
+				"includeInlayFunctionLikeReturnTypeHints":  true,
+				"includeInlayEnumMemberValueHints":         true,
+				"includeInlayFunctionParameterTypeHints":   true,
+			},
+		},
+		SymbolSeparators: []string{"."},
+		Quirks: []string{
+			"only files in an open project are indexed, so TypeScript files are opened at startup",
+		},
+	},
+	{
+		Name:             "clangd",
+		Commands:         []string{"clangd"},
+		SymbolSeparators: []string{"::"},
+		Quirks: []string{
+			"needs compile_commands.json to resolve includes; pass --compile-commands-dir if it is not in the workspace root",
+		},
+	},
+}
+
+// Profiles returns the names of the known profiles, including the default
+func Profiles() []string {
+	names := []string{DefaultProfile.Name}
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// LookupProfile returns the profile with a name
+func LookupProfile(name string) (*Profile, error) {
+	if name == DefaultProfile.Name {
+		return DefaultProfile, nil
+	}
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown profile %q, expected one of: %s", name, strings.Join(Profiles(), ", "))
+}
+
+// SplitSymbolName splits a qualified symbol name such as Type.Method at the
+// last separator used by the server. ok is false for unqualified names.
+func (p *Profile) SplitSymbolName(symbolName string) (container, member string, ok bool) {
+	index, length := -1, 0
+	for _, sep := range p.SymbolSeparators {
+		if i := strings.LastIndex(symbolName, sep); i > index {
+			index, length = i, len(sep)
+		}
+	}
+	if index <= 0 {
+		return "", symbolName, false
+	}
+	return symbolName[:index], symbolName[index+length:], true
+}
+
+// ProfileForCommand returns the profile for a server command, or the default
+// profile if the command is not recognised
+func ProfileForCommand(command string) *Profile {
+	name := strings.ToLower(filepath.Base(command))
+	name = strings.TrimSuffix(name, ".exe")
+	name = strings.TrimSuffix(name, ".cmd")
+	for _, profile := range profiles {
+		if slices.Contains(profile.Commands, name) {
+			return profile
+		}
+	}
+	return DefaultProfile
+}
+
+// Profile returns the profile the client was created with
+func (c *Client) Profile() *Profile {
+	if c.profile == nil {
+		return DefaultProfile
+	}
+	return c.profile
+}
+
+// preopenFiles opens the files the profile asks for in the workspace
+func (c *Client) preopenFiles(ctx context.Context, workspaceDir string) error {
+	profile := c.Profile()
+	if len(profile.PreopenExtensions) == 0 {
+		return nil
+	}
+
+	lspLogger.Info("Opening %s files in workspace: %s", strings.Join(profile.PreopenExtensions, ", "), workspaceDir)
+
+	// Track count of opened files for logging
+	fileCount := 0
+
+	// Walk the workspace directory
+	err := filepath.Walk(workspaceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip directories
+		if info.IsDir() {
+			// Skip node_modules, .git, and other common directories to avoid processing too many files
+			basename := filepath.Base(path)
+			if basename == "node_modules" || basename == ".git" || strings.HasPrefix(basename, ".") && path != workspaceDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if slices.Contains(profile.PreopenExtensions, filepath.Ext(path)) {
+			if err := c.OpenFile(ctx, path); err != nil {
+				lspLogger.Warn("Failed to open file %s: %v", path, err)
+				return nil // Continue with other files even if one fails
+			}
+			fileCount++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("error walking workspace directory: %w", err)
+	}
+
+	lspLogger.Info("Opened %d files", fileCount)
+	return nil
+}
@@ -0,0 +1,121 @@
+package lsp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileForCommand(t *testing.T) {
+	testCases := []struct {
+		command  string
+		expected string
+	}{
+		{"gopls", "gopls"},
+		{"/home/me/go/bin/gopls", "gopls"},
+		{"rust-analyzer", "rust-analyzer"},
+		{"pyright-langserver", "pyright"},
+		{"/usr/local/bin/typescript-language-server", "typescript-language-server"},
+		{"clangd.exe", "clangd"},
+		{"some-other-server", "default"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.command, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ProfileForCommand(tc.command).Name)
+		})
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	for _, name := range Profiles() {
+		profile, err := LookupProfile(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, profile.Name)
+	}
+
+	_, err := LookupProfile("pylance")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gopls")
+}
+
+func TestSplitSymbolName(t *testing.T) {
+	testCases := []struct {
+		profile   *Profile
+		name      string
+		container string
+		member    string
+		qualified bool
+	}{
+		{DefaultProfile, "Client", "", "Client", false},
+		{DefaultProfile, "Client.Call", "Client", "Call", true},
+		{DefaultProfile, "lsp::Client::call", "lsp::Client", "call", true},
+		{DefaultProfile, ".hidden", "", ".hidden", false},
+		{&Profile{SymbolSeparators: []string{"::"}}, "Vec::push", "Vec", "push", true},
+		{&Profile{SymbolSeparators: []string{"::"}}, "a.b", "", "a.b", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			container, member, ok := tc.profile.SplitSymbolName(tc.name)
+			assert.Equal(t, tc.qualified, ok)
+			assert.Equal(t, tc.container, container)
+			assert.Equal(t, tc.member, member)
+		})
+	}
+}
+
+func TestInitializationOptions(t *testing.T) {
+	gopls := &Client{config: DefaultClientConfig(), profile: ProfileForCommand("gopls")}
+	assert.NotNil(t, gopls.initializationOptions())
+
+	// Only gopls understands its code lens options
+	other := &Client{config: DefaultClientConfig(), profile: DefaultProfile}
+	assert.Nil(t, other.initializationOptions())
+
+	custom := map[string]any{"checkOnSave": false}
+	configured := &Client{config: &ClientConfig{InitializationOptions: custom}, profile: ProfileForCommand("gopls")}
+	assert.Equal(t, custom, configured.initializationOptions())
+}
+
+func TestGoplsInitializationOptions(t *testing.T) {
+	gopls := &Client{config: DefaultClientConfig(), profile: ProfileForCommand("gopls")}
+	options, ok := gopls.initializationOptions().(map[string]any)
+	require.True(t, ok)
+
+	// The semantic_tokens and inlay_hints tools need these turned on
+	assert.Equal(t, true, options["semanticTokens"])
+	assert.Contains(t, options, "hints")
+}
+
+func TestPreopenFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"src/index.ts", "src/app.tsx", "src/util.js", "node_modules/lib/index.ts", ".cache/gen.ts"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("export {}\n"), 0644))
+	}
+
+	client, serverReader, _ := newPipeClient(t)
+	client.profile = ProfileForCommand("typescript-language-server")
+
+	// Discard the didOpen notifications
+	go func() {
+		for {
+			if _, err := ReadMessage(serverReader); err != nil {
+				return
+			}
+		}
+	}()
+
+	require.NoError(t, client.preopenFiles(context.Background(), dir))
+	assert.True(t, client.IsFileOpen(filepath.Join(dir, "src/index.ts")))
+	assert.True(t, client.IsFileOpen(filepath.Join(dir, "src/app.tsx")))
+	assert.False(t, client.IsFileOpen(filepath.Join(dir, "src/util.js")))
+	assert.False(t, client.IsFileOpen(filepath.Join(dir, "node_modules/lib/index.ts")))
+	assert.False(t, client.IsFileOpen(filepath.Join(dir, ".cache/gen.ts")))
+}
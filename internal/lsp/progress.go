@@ -0,0 +1,298 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// ProgressInfo describes a work done progress operation that the server has
+// begun and not yet ended, such as indexing the workspace
+type ProgressInfo struct {
+	Token      string
+	Title      string
+	Message    string
+	Percentage uint32
+	Started    time.Time
+}
+
+// String returns a short human readable description of the progress
+func (p ProgressInfo) String() string {
+	var b strings.Builder
+	b.WriteString(p.Title)
+	if p.Message != "" {
+		b.WriteString(": " + p.Message)
+	}
+	if p.Percentage > 0 {
+		b.WriteString(fmt.Sprintf(" (%d%%)", p.Percentage))
+	}
+	return b.String()
+}
+
+// IndexingState summarizes whether the server has finished its startup work
+type IndexingState struct {
+	// Ready is true once WaitForServerReady has completed
+	Ready bool
+
+	// Active lists progress operations that are still running, oldest first
+	Active []ProgressInfo
+}
+
+// Busy reports whether the server is still working on something
+func (s IndexingState) Busy() bool {
+	return !s.Ready || len(s.Active) > 0
+}
+
+// ReadinessConfig controls how WaitForServerReady decides the server is ready
+type ReadinessConfig struct {
+	// Timeout is the maximum time to wait for the server to become ready.
+	// The server is used anyway once it elapses.
+	Timeout time.Duration
+
+	// QuietPeriod is how long the server must go without any active progress
+	// before it is considered ready
+	QuietPeriod time.Duration
+
+	// Probe sends a workspace/symbol request once progress has settled and
+	// waits until the server answers it successfully
+	Probe bool
+}
+
+// DefaultReadinessConfig returns a configuration with sensible defaults
+func DefaultReadinessConfig() *ReadinessConfig {
+	return &ReadinessConfig{
+		Timeout:     60 * time.Second,
+		QuietPeriod: time.Second,
+		Probe:       false,
+	}
+}
+
+// SetReadinessConfig changes how WaitForServerReady decides the server is ready
+func (c *Client) SetReadinessConfig(config *ReadinessConfig) {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	c.readiness = config
+}
+
+// IndexingState returns the current readiness of the server and any work
+// it reports as in progress
+func (c *Client) IndexingState() IndexingState {
+	c.progressMu.RLock()
+	defer c.progressMu.RUnlock()
+
+	state := IndexingState{Ready: c.ready}
+	for _, p := range c.progress {
+		state.Active = append(state.Active, *p)
+	}
+	sort.Slice(state.Active, func(i, j int) bool {
+		return state.Active[i].Started.Before(state.Active[j].Started)
+	})
+	return state
+}
+
+// WaitForServerReady blocks until the server has finished the work it reports
+// through $/progress and has been quiet for the configured period. If the
+// timeout elapses first, a warning is logged and the server is used anyway.
+func (c *Client) WaitForServerReady(ctx context.Context) error {
+	c.progressMu.Lock()
+	config := c.readiness
+	// Give the server a full quiet period to start reporting progress
+	c.progressChangedAt = time.Now()
+	c.progressMu.Unlock()
+	if config == nil {
+		config = DefaultReadinessConfig()
+	}
+
+	waitCtx := ctx
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := c.waitForProgress(waitCtx, config.QuietPeriod)
+	if err == nil && config.Probe {
+		err = c.probeWorkspaceSymbols(waitCtx)
+	}
+
+	if err != nil {
+		// The caller gave up, rather than our own timeout
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		state := c.IndexingState()
+		lspLogger.Warn("Server not ready after %s, continuing anyway (%d operations in progress)",
+			time.Since(start).Round(time.Millisecond), len(state.Active))
+	} else {
+		lspLogger.Info("Server ready after %s", time.Since(start).Round(time.Millisecond))
+	}
+
+	c.progressMu.Lock()
+	c.ready = true
+	c.progressMu.Unlock()
+
+	return nil
+}
+
+// waitForProgress waits until no progress is active and none has been
+// reported for quietPeriod
+func (c *Client) waitForProgress(ctx context.Context, quietPeriod time.Duration) error {
+	for {
+		c.progressMu.RLock()
+		active := len(c.progress)
+		lastChange := c.progressChangedAt
+		changed := c.progressChanged
+		c.progressMu.RUnlock()
+
+		wait := quietPeriod - time.Since(lastChange)
+		if active == 0 && wait <= 0 {
+			return nil
+		}
+		if active > 0 {
+			// Nothing to do until the active progress reports something
+			wait = quietPeriod
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// probeWorkspaceSymbols issues workspace/symbol requests until the server
+// answers one without an error. Some servers fail requests while loading.
+func (c *Client) probeWorkspaceSymbols(ctx context.Context) error {
+	for {
+		_, err := c.Symbol(ctx, protocol.WorkspaceSymbolParams{Query: ""})
+		if err == nil {
+			return nil
+		}
+		lspLogger.Debug("Readiness probe failed: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// resetProgress forgets all progress, e.g. because the server was restarted
+func (c *Client) resetProgress() {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	c.progress = make(map[string]*ProgressInfo)
+	c.progressEnded = make(map[string]bool)
+	c.ready = false
+	c.notifyProgressChangedLocked()
+}
+
+// notifyProgressChangedLocked wakes up anything waiting for progress to
+// change. progressMu must be held.
+func (c *Client) notifyProgressChangedLocked() {
+	c.progressChangedAt = time.Now()
+	close(c.progressChanged)
+	c.progressChanged = make(chan struct{})
+}
+
+func progressTokenString(token protocol.ProgressToken) string {
+	return fmt.Sprint(token.Value)
+}
+
+// HandleWorkDoneProgressCreate processes window/workDoneProgress/create requests
+func HandleWorkDoneProgressCreate(params json.RawMessage) (any, error) {
+	var createParams protocol.WorkDoneProgressCreateParams
+	if err := json.Unmarshal(params, &createParams); err != nil {
+		lspLogger.Error("Error unmarshaling progress create params: %v", err)
+		return nil, err
+	}
+
+	// Nothing to track until the server begins reporting on the token
+	lspLogger.Debug("Progress token created: %s", progressTokenString(createParams.Token))
+	return nil, nil
+}
+
+// HandleProgress processes $/progress notifications for work done progress
+func HandleProgress(client *Client, params json.RawMessage) {
+	var progressParams protocol.ProgressParams
+	if err := json.Unmarshal(params, &progressParams); err != nil {
+		lspLogger.Error("Error unmarshaling progress params: %v", err)
+		return
+	}
+
+	// The value is one of WorkDoneProgressBegin, Report or End, told apart by kind
+	raw, err := json.Marshal(progressParams.Value)
+	if err != nil {
+		lspLogger.Error("Error marshaling progress value: %v", err)
+		return
+	}
+	var value struct {
+		Kind       string  `json:"kind"`
+		Title      string  `json:"title"`
+		Message    string  `json:"message"`
+		Percentage *uint32 `json:"percentage"`
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		// Partial result progress carries arbitrary values; ignore it
+		lspLogger.Debug("Ignoring non work done progress: %s", string(raw))
+		return
+	}
+
+	token := progressTokenString(progressParams.Token)
+
+	client.progressMu.Lock()
+	defer client.progressMu.Unlock()
+
+	switch value.Kind {
+	case "begin":
+		info := &ProgressInfo{
+			Token:   token,
+			Title:   value.Title,
+			Message: value.Message,
+			Started: time.Now(),
+		}
+		if value.Percentage != nil {
+			info.Percentage = *value.Percentage
+		}
+		// Notifications are handled concurrently, so the end may have been seen first
+		if client.progressEnded[token] {
+			delete(client.progressEnded, token)
+			return
+		}
+		client.progress[token] = info
+		lspLogger.Info("Server progress started: %s", info)
+	case "report":
+		info, ok := client.progress[token]
+		if !ok {
+			return
+		}
+		if value.Message != "" {
+			info.Message = value.Message
+		}
+		if value.Percentage != nil {
+			info.Percentage = *value.Percentage
+		}
+		lspLogger.Debug("Server progress: %s", info)
+	case "end":
+		info, ok := client.progress[token]
+		if !ok {
+			client.progressEnded[token] = true
+			return
+		}
+		delete(client.progress, token)
+		lspLogger.Info("Server progress finished: %s (%s)", info.Title, time.Since(info.Started).Round(time.Millisecond))
+	default:
+		return
+	}
+
+	client.notifyProgressChangedLocked()
+}
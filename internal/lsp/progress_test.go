@@ -0,0 +1,98 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sendProgress(client *Client, token any, value string) {
+	params, _ := json.Marshal(map[string]any{
+		"token": token,
+		"value": json.RawMessage(value),
+	})
+	HandleProgress(client, params)
+}
+
+func TestProgressTracking(t *testing.T) {
+	client, _, _ := newPipeClient(t)
+
+	sendProgress(client, "index", `{"kind":"begin","title":"Indexing","message":"0/10","percentage":0}`)
+	sendProgress(client, 7, `{"kind":"begin","title":"Loading packages"}`)
+
+	state := client.IndexingState()
+	require.Len(t, state.Active, 2)
+	assert.True(t, state.Busy())
+	assert.Equal(t, "Indexing", state.Active[0].Title)
+
+	sendProgress(client, "index", `{"kind":"report","message":"5/10","percentage":50}`)
+	state = client.IndexingState()
+	assert.Equal(t, "Indexing: 5/10 (50%)", state.Active[0].String())
+
+	sendProgress(client, "index", `{"kind":"end"}`)
+	sendProgress(client, 7, `{"kind":"end"}`)
+	assert.Empty(t, client.IndexingState().Active)
+}
+
+func TestProgressEndBeforeBegin(t *testing.T) {
+	client, _, _ := newPipeClient(t)
+
+	// Notifications are dispatched concurrently and may arrive out of order
+	sendProgress(client, "late", `{"kind":"end"}`)
+	sendProgress(client, "late", `{"kind":"begin","title":"Indexing"}`)
+
+	assert.Empty(t, client.IndexingState().Active)
+}
+
+func TestWaitForServerReadyWaitsForProgress(t *testing.T) {
+	client, _, _ := newPipeClient(t)
+	client.SetReadinessConfig(&ReadinessConfig{
+		Timeout:     5 * time.Second,
+		QuietPeriod: 50 * time.Millisecond,
+	})
+
+	sendProgress(client, "index", `{"kind":"begin","title":"Indexing"}`)
+
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, client.WaitForServerReady(context.Background()))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("server reported ready while indexing")
+	case <-time.After(200 * time.Millisecond):
+	}
+	assert.False(t, client.IndexingState().Ready)
+
+	sendProgress(client, "index", `{"kind":"end"}`)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not become ready after indexing ended")
+	}
+	assert.True(t, client.IndexingState().Ready)
+	assert.False(t, client.IndexingState().Busy())
+}
+
+func TestWaitForServerReadyTimeout(t *testing.T) {
+	client, _, _ := newPipeClient(t)
+	client.SetReadinessConfig(&ReadinessConfig{
+		Timeout:     100 * time.Millisecond,
+		QuietPeriod: 10 * time.Millisecond,
+	})
+
+	sendProgress(client, "stuck", `{"kind":"begin","title":"Indexing"}`)
+
+	// A server that never finishes indexing is used anyway
+	assert.NoError(t, client.WaitForServerReady(context.Background()))
+	state := client.IndexingState()
+	assert.True(t, state.Ready)
+	assert.True(t, state.Busy())
+}
@@ -0,0 +1,130 @@
+package lsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// ErrNoServer is returned when no language server handles a file
+var ErrNoServer = errors.New("no language server configured for file")
+
+// Route sends files to a language server by language or path
+type Route struct {
+	// Name identifies the server in logs and tool output, e.g. "gopls"
+	Name string
+
+	Client *Client
+
+	// LanguageIDs are the languages handled by the server, as returned by
+	// DetectLanguageID, e.g. "go" or "typescript"
+	LanguageIDs []string
+
+	// Globs are file patterns handled by the server, relative to the workspace
+	Globs []string
+}
+
+// matches reports whether the route handles a file. A route without any
+// languages or globs handles every file.
+func (r *Route) matches(path, workspaceDir string) bool {
+	if len(r.LanguageIDs) == 0 && len(r.Globs) == 0 {
+		return true
+	}
+
+	languageID := string(DetectLanguageID(path))
+	if slices.Contains(r.LanguageIDs, languageID) {
+		return true
+	}
+	for _, glob := range r.Globs {
+		if utilities.MatchPathGlob(glob, path, workspaceDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Router manages the language servers for a workspace and decides which one
+// handles each file. It implements the client interface used by the
+// workspace watcher so one watcher can serve every server.
+type Router struct {
+	workspaceDir string
+	routes       []*Route
+}
+
+// NewRouter creates a router. Files are sent to the first matching route.
+func NewRouter(workspaceDir string, routes ...*Route) *Router {
+	return &Router{
+		workspaceDir: workspaceDir,
+		routes:       routes,
+	}
+}
+
+// Routes returns the configured routes in order
+func (r *Router) Routes() []*Route {
+	return r.routes
+}
+
+// Clients returns every language server client
+func (r *Router) Clients() []*Client {
+	clients := make([]*Client, 0, len(r.routes))
+	for _, route := range r.routes {
+		clients = append(clients, route.Client)
+	}
+	return clients
+}
+
+// ClientForFile returns the client of the language server that handles a file
+func (r *Router) ClientForFile(path string) (*Client, error) {
+	path = strings.TrimPrefix(path, "file://")
+	for _, route := range r.routes {
+		if route.matches(path, r.workspaceDir) {
+			return route.Client, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoServer, path)
+}
+
+// IsFileOpen checks if a file is open in the server that handles it
+func (r *Router) IsFileOpen(path string) bool {
+	client, err := r.ClientForFile(path)
+	if err != nil {
+		return false
+	}
+	return client.IsFileOpen(path)
+}
+
+// OpenFile opens a file in the server that handles it. Files that no server
+// handles are skipped.
+func (r *Router) OpenFile(ctx context.Context, path string) error {
+	client, err := r.ClientForFile(path)
+	if err != nil {
+		return nil
+	}
+	return client.OpenFile(ctx, path)
+}
+
+// NotifyChange notifies the server that handles a file that it changed
+func (r *Router) NotifyChange(ctx context.Context, path string) error {
+	client, err := r.ClientForFile(path)
+	if err != nil {
+		return err
+	}
+	return client.NotifyChange(ctx, path)
+}
+
+// DidChangeWatchedFiles sends watched file events to every server. Servers
+// also watch files they don't open, such as go.mod or tsconfig.json.
+func (r *Router) DidChangeWatchedFiles(ctx context.Context, params protocol.DidChangeWatchedFilesParams) error {
+	var errs []error
+	for _, route := range r.routes {
+		if err := route.Client.DidChangeWatchedFiles(ctx, params); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", route.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
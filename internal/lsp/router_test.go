@@ -0,0 +1,63 @@
+package lsp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterClientForFile(t *testing.T) {
+	gopls := &Client{}
+	tsserver := &Client{}
+	pyright := &Client{}
+
+	router := NewRouter("/ws",
+		&Route{Name: "gopls", Client: gopls, LanguageIDs: []string{"go"}},
+		&Route{Name: "tsserver", Client: tsserver, LanguageIDs: []string{"typescript", "javascript"}},
+		&Route{Name: "pyright", Client: pyright, Globs: []string{"scripts/**/*.py"}},
+	)
+
+	testCases := []struct {
+		path     string
+		expected *Client
+	}{
+		{"/ws/cmd/main.go", gopls},
+		{"file:///ws/cmd/main.go", gopls},
+		{"/ws/web/index.ts", tsserver},
+		{"/ws/web/app.js", tsserver},
+		{"/ws/scripts/tools/gen.py", pyright},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			client, err := router.ClientForFile(tc.path)
+			require.NoError(t, err)
+			assert.Same(t, tc.expected, client)
+		})
+	}
+
+	// Python outside the glob isn't handled by anyone
+	_, err := router.ClientForFile("/ws/other/gen.py")
+	assert.True(t, errors.Is(err, ErrNoServer), "expected no server error, got %v", err)
+	assert.Len(t, router.Clients(), 3)
+}
+
+func TestRouterCatchAllRoute(t *testing.T) {
+	gopls := &Client{}
+	fallback := &Client{}
+
+	router := NewRouter("/ws",
+		&Route{Name: "gopls", Client: gopls, LanguageIDs: []string{"go"}},
+		&Route{Name: "fallback", Client: fallback},
+	)
+
+	client, err := router.ClientForFile("/ws/main.go")
+	require.NoError(t, err)
+	assert.Same(t, gopls, client)
+
+	client, err = router.ClientForFile("/ws/README.md")
+	require.NoError(t, err)
+	assert.Same(t, fallback, client)
+}
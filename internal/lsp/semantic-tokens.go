@@ -0,0 +1,52 @@
+package lsp
+
+import (
+	"encoding/json"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// semanticTokensOptions returns the server's semantic tokens options, and
+// whether it provides semantic tokens
+func (c *Client) semanticTokensOptions() (protocol.SemanticTokensOptions, bool) {
+	c.capabilitiesMu.RLock()
+	provider := c.capabilities.SemanticTokensProvider
+	c.capabilitiesMu.RUnlock()
+
+	var options protocol.SemanticTokensOptions
+	if provider == nil {
+		return options, false
+	}
+
+	// The provider is decoded as a generic value since it may be options or
+	// registration options
+	data, err := json.Marshal(provider)
+	if err != nil {
+		return options, false
+	}
+	if err := json.Unmarshal(data, &options); err != nil {
+		lspLogger.Warn("Could not parse semantic tokens options: %v", err)
+		return options, false
+	}
+	return options, len(options.Legend.TokenTypes) > 0
+}
+
+// SemanticTokensLegend returns the legend the server encodes semantic token
+// types and modifiers with, and whether it provides semantic tokens
+func (c *Client) SemanticTokensLegend() (protocol.SemanticTokensLegend, bool) {
+	options, ok := c.semanticTokensOptions()
+	return options.Legend, ok
+}
+
+// SupportsSemanticTokensRange reports whether the server answers
+// textDocument/semanticTokens/range requests
+func (c *Client) SupportsSemanticTokensRange() bool {
+	options, ok := c.semanticTokensOptions()
+	if !ok || options.Range == nil {
+		return false
+	}
+	if supported, isBool := options.Range.Value.(bool); isBool {
+		return supported
+	}
+	return options.Range.Value != nil
+}
@@ -0,0 +1,35 @@
+package lsp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemanticTokensCapabilities(t *testing.T) {
+	withProvider := func(provider string) *Client {
+		var capabilities protocol.ServerCapabilities
+		require.NoError(t, json.Unmarshal([]byte(`{"semanticTokensProvider": `+provider+`}`), &capabilities))
+		return &Client{capabilities: capabilities}
+	}
+
+	client := withProvider(`{"legend": {"tokenTypes": ["namespace", "type"], "tokenModifiers": ["definition"]}, "full": true, "range": true}`)
+	legend, ok := client.SemanticTokensLegend()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"namespace", "type"}, legend.TokenTypes)
+	assert.Equal(t, []string{"definition"}, legend.TokenModifiers)
+	assert.True(t, client.SupportsSemanticTokensRange())
+
+	client = withProvider(`{"legend": {"tokenTypes": ["variable"], "tokenModifiers": []}, "full": {"delta": true}}`)
+	_, ok = client.SemanticTokensLegend()
+	assert.True(t, ok)
+	assert.False(t, client.SupportsSemanticTokensRange())
+
+	client = withProvider(`null`)
+	_, ok = client.SemanticTokensLegend()
+	assert.False(t, ok)
+	assert.False(t, client.SupportsSemanticTokensRange())
+}
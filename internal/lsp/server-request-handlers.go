@@ -20,11 +20,31 @@ func RegisterFileWatchHandler(handler FileWatchHandler) {
 
 // Requests
 
-func HandleWorkspaceConfiguration(params json.RawMessage) (any, error) {
-	return []map[string]any{{}}, nil
+// HandleWorkspaceConfiguration answers each requested section for its scope
+// from the client's settings. Unknown sections get an empty object.
+func HandleWorkspaceConfiguration(client *Client, params json.RawMessage) (any, error) {
+	var configParams protocol.ParamConfiguration
+	if err := json.Unmarshal(params, &configParams); err != nil {
+		lspLogger.Error("Error unmarshaling configuration params: %v", err)
+		return nil, err
+	}
+
+	results := make([]any, 0, len(configParams.Items))
+	for _, item := range configParams.Items {
+		var scopeURI string
+		if item.ScopeURI != nil {
+			scopeURI = string(*item.ScopeURI)
+		}
+		value, ok := client.settings.Section(item.Section, scopeURI)
+		if !ok {
+			value = map[string]any{}
+		}
+		results = append(results, value)
+	}
+	return results, nil
 }
 
-func HandleRegisterCapability(params json.RawMessage) (any, error) {
+func HandleRegisterCapability(client *Client, params json.RawMessage) (any, error) {
 	var registerParams protocol.RegistrationParams
 	if err := json.Unmarshal(params, &registerParams); err != nil {
 		lspLogger.Error("Error unmarshaling registration params: %v", err)
@@ -50,10 +70,22 @@ func HandleRegisterCapability(params json.RawMessage) (any, error) {
 				continue
 			}
 
+			client.recordWatchRegistration(reg.ID, opts.Watchers)
+
 			// Notify file watchers
 			if fileWatchHandler != nil {
-				fileWatchHandler(reg.ID, opts.Watchers)
+				fileWatchHandler(client.watchRegistrationKey(reg.ID), opts.Watchers)
 			}
+		} else if reg.Method == "textDocument/diagnostic" {
+			var opts protocol.DiagnosticRegistrationOptions
+			if optJson, err := json.Marshal(reg.RegisterOptions); err == nil {
+				_ = json.Unmarshal(optJson, &opts)
+			}
+
+			client.capabilitiesMu.Lock()
+			client.pullDiagnostics = true
+			client.pullWorkspaceDiagnostics = client.pullWorkspaceDiagnostics || opts.WorkspaceDiagnostics
+			client.capabilitiesMu.Unlock()
 		}
 	}
 
@@ -120,9 +152,7 @@ func HandleDiagnostics(client *Client, params json.RawMessage) {
 	}
 
 	// Save diagnostics in client
-	client.diagnosticsMu.Lock()
-	client.diagnostics[diagParams.URI] = diagParams.Diagnostics
-	client.diagnosticsMu.Unlock()
+	client.storeDiagnostics(diagParams.URI, diagParams.Version, diagParams.Diagnostics, "")
 
 	lspLogger.Info("Received diagnostics for %s: %d items", diagParams.URI, len(diagParams.Diagnostics))
 }
@@ -0,0 +1,43 @@
+package lsp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWorkspaceConfiguration(t *testing.T) {
+	defaults := map[string]any{
+		"gopls": map[string]any{"staticcheck": true},
+		"typescript": map[string]any{
+			"format": map[string]any{"semicolons": "remove"},
+		},
+	}
+	client := &Client{settings: NewSettingsStore(defaults)}
+	client.settings.Set(&Settings{
+		Scopes: []ScopedSettings{{
+			Path:     "/ws/legacy",
+			Sections: map[string]any{"gopls": map[string]any{"staticcheck": false}},
+		}},
+	})
+
+	params := json.RawMessage(`{"items": [
+		{"section": "gopls"},
+		{"section": "typescript.format"},
+		{"section": "python"},
+		{},
+		{"section": "gopls", "scopeUri": "file:///ws/legacy/main.go"}
+	]}`)
+	result, err := HandleWorkspaceConfiguration(client, params)
+	require.NoError(t, err)
+
+	items := result.([]any)
+	require.Len(t, items, 5)
+	assert.Equal(t, map[string]any{"staticcheck": true}, items[0])
+	assert.Equal(t, map[string]any{"semicolons": "remove"}, items[1])
+	assert.Equal(t, map[string]any{}, items[2], "unknown sections get an empty object")
+	assert.Equal(t, defaults, items[3])
+	assert.Equal(t, map[string]any{"staticcheck": false}, items[4])
+}
@@ -0,0 +1,242 @@
+package lsp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// settingsReloadDelay batches the several events editors produce when saving
+// a settings file
+const settingsReloadDelay = 100 * time.Millisecond
+
+// Settings are returned to the server's workspace/configuration requests
+type Settings struct {
+	// Sections maps section names to values, e.g. {"gopls": {"staticcheck":
+	// true}}. Dotted names such as "python.analysis" are nested.
+	Sections map[string]any `json:"settings"`
+
+	// Scopes override sections for files under a directory
+	Scopes []ScopedSettings `json:"scopes"`
+}
+
+// ScopedSettings override settings for files under a directory
+type ScopedSettings struct {
+	// Path is the directory, relative to the workspace or absolute
+	Path string `json:"path"`
+
+	Sections map[string]any `json:"settings"`
+}
+
+// LoadSettings reads a settings file. Relative scope paths are resolved
+// against workspaceDir.
+func LoadSettings(settingsPath, workspaceDir string) (*Settings, error) {
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var settings Settings
+	if err := decoder.Decode(&settings); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid settings file %s: %w", settingsPath, err)
+	}
+
+	for i, scope := range settings.Scopes {
+		if scope.Path == "" {
+			return nil, fmt.Errorf("invalid settings file %s: scopes[%d]: path is required", settingsPath, i)
+		}
+		if !filepath.IsAbs(scope.Path) {
+			settings.Scopes[i].Path = filepath.Join(workspaceDir, scope.Path)
+		}
+		settings.Scopes[i].Path = filepath.Clean(settings.Scopes[i].Path)
+	}
+	return &settings, nil
+}
+
+// SettingsStore answers workspace/configuration requests. Settings from a
+// file are layered over the defaults the server was configured with.
+type SettingsStore struct {
+	mu       sync.RWMutex
+	defaults map[string]any
+	settings *Settings
+}
+
+// NewSettingsStore creates a store with default sections
+func NewSettingsStore(defaults map[string]any) *SettingsStore {
+	return &SettingsStore{defaults: defaults}
+}
+
+// Set replaces the settings layered over the defaults
+func (s *SettingsStore) Set(settings *Settings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings = settings
+}
+
+// Section returns the value of a section for a scope. An empty section
+// returns every setting and an empty scope ignores scoped settings.
+func (s *SettingsStore) Section(section string, scopeURI string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	merged := mergeSettings(nil, s.defaults)
+	if s.settings != nil {
+		merged = mergeSettings(merged, s.settings.Sections)
+		for _, scope := range s.matchingScopes(scopeURI) {
+			merged = mergeSettings(merged, scope.Sections)
+		}
+	}
+
+	if section == "" {
+		return merged, len(merged) > 0
+	}
+
+	var current any = merged
+	for _, key := range strings.Split(section, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// matchingScopes returns the scopes containing a document or directory, least
+// specific first so that deeper directories take precedence
+func (s *SettingsStore) matchingScopes(scopeURI string) []ScopedSettings {
+	if scopeURI == "" {
+		return nil
+	}
+	path := filepath.Clean(strings.TrimPrefix(scopeURI, "file://"))
+
+	var scopes []ScopedSettings
+	for _, scope := range s.settings.Scopes {
+		if path == scope.Path || strings.HasPrefix(path, scope.Path+string(filepath.Separator)) {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.SliceStable(scopes, func(i, j int) bool {
+		return len(scopes[i].Path) < len(scopes[j].Path)
+	})
+	return scopes
+}
+
+// mergeSettings returns a copy of dst with src merged into it. Nested objects
+// are merged recursively and dotted keys are expanded into nested objects.
+func mergeSettings(dst map[string]any, src map[string]any) map[string]any {
+	merged := make(map[string]any, len(dst)+len(src))
+	for key, value := range dst {
+		merged[key] = value
+	}
+
+	for key, value := range src {
+		// "python.analysis": {...} is the same as "python": {"analysis": {...}}
+		if first, rest, ok := strings.Cut(key, "."); ok {
+			value = map[string]any{rest: value}
+			key = first
+		}
+
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := merged[key].(map[string]any)
+		if srcIsMap {
+			if !dstIsMap {
+				dstMap = nil
+			}
+			merged[key] = mergeSettings(dstMap, srcMap)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// Settings returns the store that answers the server's configuration requests
+func (c *Client) Settings() *SettingsStore {
+	return c.settings
+}
+
+// UpdateSettings replaces the client's settings and tells the server they
+// changed. Servers that pull configuration ask for the sections they need.
+func (c *Client) UpdateSettings(ctx context.Context, settings *Settings) error {
+	c.settings.Set(settings)
+
+	all, _ := c.settings.Section("", "")
+	return c.DidChangeConfiguration(ctx, protocol.DidChangeConfigurationParams{
+		Settings: all,
+	})
+}
+
+// WatchSettingsFile calls onChange with the new settings whenever the settings
+// file is written, until the context is cancelled. Files that fail to parse
+// are logged and skipped.
+func WatchSettingsFile(ctx context.Context, settingsPath, workspaceDir string, onChange func(*Settings)) error {
+	settingsPath, err := filepath.Abs(settingsPath)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create settings watcher: %w", err)
+	}
+
+	// Watch the directory since editors often replace the file when saving
+	if err := watcher.Add(filepath.Dir(settingsPath)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch settings file: %w", err)
+	}
+
+	go func() {
+		defer func() { _ = watcher.Close() }()
+
+		var reload <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != settingsPath || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				reload = time.After(settingsReloadDelay)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				lspLogger.Error("Settings watcher error: %v", err)
+			case <-reload:
+				reload = nil
+				settings, err := LoadSettings(settingsPath, workspaceDir)
+				if err != nil {
+					lspLogger.Error("Ignoring settings change: %v", err)
+					continue
+				}
+				lspLogger.Info("Reloaded settings from %s", settingsPath)
+				onChange(settings)
+			}
+		}
+	}()
+
+	return nil
+}
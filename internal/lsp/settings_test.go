@@ -0,0 +1,134 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsStoreSection(t *testing.T) {
+	store := NewSettingsStore(map[string]any{
+		"python": map[string]any{
+			"analysis": map[string]any{"typeCheckingMode": "basic", "autoImportCompletions": true},
+		},
+	})
+
+	value, ok := store.Section("python.analysis.typeCheckingMode", "")
+	require.True(t, ok)
+	assert.Equal(t, "basic", value)
+
+	store.Set(&Settings{
+		Sections: map[string]any{
+			"python.analysis": map[string]any{"typeCheckingMode": "strict"},
+		},
+		Scopes: []ScopedSettings{
+			{Path: "/ws/scripts", Sections: map[string]any{"python.analysis.typeCheckingMode": "off"}},
+			{Path: "/ws", Sections: map[string]any{"python.analysis.typeCheckingMode": "standard"}},
+		},
+	})
+
+	// Nested objects are merged rather than replaced
+	value, ok = store.Section("python.analysis", "")
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"typeCheckingMode": "strict", "autoImportCompletions": true}, value)
+
+	testCases := []struct {
+		scopeURI string
+		expected string
+	}{
+		{"", "strict"},
+		{"file:///elsewhere/main.py", "strict"},
+		{"file:///ws", "standard"},
+		{"file:///ws/app/main.py", "standard"},
+		{"file:///ws/scripts/gen.py", "off"},
+		{"file:///ws/scriptsx/gen.py", "standard"},
+	}
+	for _, tc := range testCases {
+		value, ok := store.Section("python.analysis.typeCheckingMode", tc.scopeURI)
+		require.True(t, ok, tc.scopeURI)
+		assert.Equal(t, tc.expected, value, tc.scopeURI)
+	}
+
+	_, ok = store.Section("rust-analyzer", "")
+	assert.False(t, ok)
+	_, ok = store.Section("python.analysis.typeCheckingMode.extra", "")
+	assert.False(t, ok)
+}
+
+func TestLoadSettings(t *testing.T) {
+	dir := t.TempDir()
+	settingsPath := filepath.Join(dir, "settings.json")
+
+	require.NoError(t, os.WriteFile(settingsPath, []byte(`{
+		"settings": {"gopls": {"staticcheck": true}},
+		"scopes": [{"path": "legacy", "settings": {"gopls": {"staticcheck": false}}}]
+	}`), 0644))
+
+	settings, err := LoadSettings(settingsPath, "/ws")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"staticcheck": true}, settings.Sections["gopls"])
+	require.Len(t, settings.Scopes, 1)
+	assert.Equal(t, filepath.Join("/ws", "legacy"), settings.Scopes[0].Path)
+
+	require.NoError(t, os.WriteFile(settingsPath, []byte(`{"gopls": {"staticcheck": true}}`), 0644))
+	_, err = LoadSettings(settingsPath, "/ws")
+	assert.Error(t, err, "sections must be under settings")
+
+	require.NoError(t, os.WriteFile(settingsPath, []byte(`{"scopes": [{"settings": {}}]}`), 0644))
+	_, err = LoadSettings(settingsPath, "/ws")
+	assert.Error(t, err)
+}
+
+func TestUpdateSettingsNotifiesServer(t *testing.T) {
+	client, serverReader, _ := newPipeClient(t)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- client.UpdateSettings(context.Background(), &Settings{
+			Sections: map[string]any{"gopls": map[string]any{"staticcheck": true}},
+		})
+	}()
+
+	msg, err := ReadMessage(serverReader)
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+	assert.Equal(t, "workspace/didChangeConfiguration", msg.Method)
+
+	var params struct {
+		Settings map[string]any `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(msg.Params, &params))
+	assert.Equal(t, map[string]any{"staticcheck": true}, params.Settings["gopls"])
+}
+
+func TestWatchSettingsFile(t *testing.T) {
+	dir := t.TempDir()
+	settingsPath := filepath.Join(dir, "settings.json")
+	require.NoError(t, os.WriteFile(settingsPath, []byte(`{}`), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan *Settings, 10)
+	require.NoError(t, WatchSettingsFile(ctx, settingsPath, dir, func(settings *Settings) {
+		changes <- settings
+	}))
+
+	// Invalid files are skipped
+	require.NoError(t, os.WriteFile(settingsPath, []byte(`{"settings": `), 0644))
+	time.Sleep(2 * settingsReloadDelay)
+	require.NoError(t, os.WriteFile(settingsPath, []byte(`{"settings": {"gopls": {"staticcheck": true}}}`), 0644))
+
+	select {
+	case settings := <-changes:
+		assert.Equal(t, map[string]any{"staticcheck": true}, settings.Sections["gopls"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("settings change not reported")
+	}
+}
@@ -0,0 +1,137 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SupervisorConfig controls how a crashed language server is restarted
+type SupervisorConfig struct {
+	// InitialBackoff is the delay before the first restart attempt
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between consecutive restart attempts. A server
+	// that stays up for longer than this is considered stable again.
+	MaxBackoff time.Duration
+
+	// MaxRestarts is the number of restarts attempted without the server
+	// becoming stable before giving up. Zero means no limit.
+	MaxRestarts int
+}
+
+// DefaultSupervisorConfig returns a configuration with sensible defaults
+func DefaultSupervisorConfig() *SupervisorConfig {
+	return &SupervisorConfig{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		MaxRestarts:    5,
+	}
+}
+
+// Supervisor restarts a client's language server when it exits unexpectedly
+// and restores the state the server had before the crash
+type Supervisor struct {
+	client       *Client
+	workspaceDir string
+	config       *SupervisorConfig
+}
+
+// NewSupervisor creates a new supervisor with default configuration
+func NewSupervisor(client *Client, workspaceDir string) *Supervisor {
+	return NewSupervisorWithConfig(client, workspaceDir, DefaultSupervisorConfig())
+}
+
+// NewSupervisorWithConfig creates a new supervisor with custom configuration
+func NewSupervisorWithConfig(client *Client, workspaceDir string, config *SupervisorConfig) *Supervisor {
+	return &Supervisor{
+		client:       client,
+		workspaceDir: workspaceDir,
+		config:       config,
+	}
+}
+
+// Run watches the language server process and restarts it when it exits,
+// until ctx is done, the client is closed, or restarts keep failing
+func (s *Supervisor) Run(ctx context.Context) {
+	backoff := s.config.InitialBackoff
+	attempts := 0
+	startedAt := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.client.Exited():
+		}
+
+		if s.client.IsClosing() || ctx.Err() != nil {
+			return
+		}
+
+		lspLogger.Error("Language server exited unexpectedly: %v", s.client.ExitError())
+
+		// Only keep backing off if the server is crashing repeatedly
+		if time.Since(startedAt) > s.config.MaxBackoff {
+			backoff = s.config.InitialBackoff
+			attempts = 0
+		}
+
+		// Remember what was open before the crash so it survives failed attempts
+		files := s.client.resetOpenFiles()
+		s.client.clearDiagnostics()
+
+		for {
+			if s.config.MaxRestarts > 0 && attempts >= s.config.MaxRestarts {
+				lspLogger.Error("Language server failed %d restarts, giving up", attempts)
+				return
+			}
+			attempts++
+
+			lspLogger.Info("Restarting language server in %s (attempt %d)", backoff, attempts)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(backoff*2, s.config.MaxBackoff)
+
+			err := s.restart(ctx, files)
+			if err == nil {
+				break
+			}
+			lspLogger.Error("Failed to restart language server: %v", err)
+		}
+
+		startedAt = time.Now()
+		lspLogger.Info("Language server restarted, reopened %d files", len(files))
+	}
+}
+
+// restart starts a new server process and brings it back to the state the
+// previous one was in: initialized, with the same files open and the same
+// file watchers registered
+func (s *Supervisor) restart(ctx context.Context, files []string) error {
+	if err := s.client.restart(); err != nil {
+		return err
+	}
+
+	if _, err := s.client.InitializeLSPClient(ctx, s.workspaceDir); err != nil {
+		return fmt.Errorf("initialize failed: %w", err)
+	}
+
+	if err := s.client.WaitForServerReady(ctx); err != nil {
+		return fmt.Errorf("server failed to become ready: %w", err)
+	}
+
+	for _, path := range files {
+		if err := s.client.OpenFile(ctx, path); err != nil {
+			lspLogger.Warn("Failed to reopen %s: %v", path, err)
+		}
+	}
+
+	s.client.replayWatchRegistrations()
+
+	return nil
+}
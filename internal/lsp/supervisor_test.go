@@ -0,0 +1,188 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServerLogEnv names the file the fake language server logs received
+// messages to. The test binary acts as the server when it is set.
+const fakeServerLogEnv = "LSP_FAKE_SERVER_LOG"
+
+func TestMain(m *testing.M) {
+	if logPath := os.Getenv(fakeServerLogEnv); logPath != "" {
+		runFakeServer(logPath)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeServer answers requests on stdin with empty results and logs the
+// method and document of every message received, one per line
+func runFakeServer(logPath string) {
+	log, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		os.Exit(1)
+	}
+	defer log.Close()
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		msg, err := ReadMessage(reader)
+		if err != nil {
+			return
+		}
+
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		fmt.Fprintln(log, strings.TrimSpace(msg.Method+" "+params.TextDocument.URI))
+
+		if msg.ID == nil {
+			if msg.Method == "exit" {
+				return
+			}
+			continue
+		}
+		result := json.RawMessage("null")
+		if msg.Method == "initialize" {
+			result = json.RawMessage(`{"capabilities": {}}`)
+		}
+		if err := WriteMessage(os.Stdout, &Message{JSONRPC: "2.0", ID: msg.ID, Result: result}); err != nil {
+			return
+		}
+	}
+}
+
+// startFakeServer starts and initializes a client for the fake language
+// server, returning it with the path of the server's message log
+func startFakeServer(t *testing.T, workspaceDir string) (*Client, string) {
+	t.Helper()
+
+	logPath := filepath.Join(t.TempDir(), "messages.log")
+	config := DefaultClientConfig()
+	config.Env = map[string]string{fakeServerLogEnv: logPath}
+
+	client, err := NewClientWithConfig(config, os.Args[0])
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	client.SetReadinessConfig(&ReadinessConfig{Timeout: time.Second, QuietPeriod: 10 * time.Millisecond})
+
+	_, err = client.InitializeLSPClient(context.Background(), workspaceDir)
+	require.NoError(t, err)
+	return client, logPath
+}
+
+// killServer kills the client's current server process
+func killServer(t *testing.T, client *Client) {
+	t.Helper()
+	client.procMu.RLock()
+	cmd := client.Cmd
+	client.procMu.RUnlock()
+	require.NoError(t, cmd.Process.Kill())
+}
+
+func TestSupervisorRestartsServer(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0644))
+
+	client, logPath := startFakeServer(t, dir)
+	require.NoError(t, client.OpenFile(context.Background(), path))
+
+	watchers := []protocol.FileSystemWatcher{{GlobPattern: protocol.GlobPattern{Value: "**/*.go"}}}
+	client.recordWatchRegistration("watch-go", watchers)
+
+	registrations := make(chan string, 1)
+	RegisterFileWatchHandler(func(id string, replayed []protocol.FileSystemWatcher) {
+		assert.Equal(t, watchers, replayed)
+		registrations <- id
+	})
+	t.Cleanup(func() { RegisterFileWatchHandler(nil) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	supervisor := NewSupervisorWithConfig(client, dir, &SupervisorConfig{
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		MaxRestarts:    3,
+	})
+	go supervisor.Run(ctx)
+
+	killServer(t, client)
+
+	// Registrations are replayed last, once the new server is initialized
+	select {
+	case id := <-registrations:
+		assert.Equal(t, client.watchRegistrationKey("watch-go"), id)
+	case <-time.After(10 * time.Second):
+		t.Fatal("watch registrations were not replayed")
+	}
+
+	assert.True(t, client.IsFileOpen(path))
+
+	// The second server is initialized and then sent the open file.
+	// Notifications aren't answered, so wait for the server to log it.
+	didOpen := "textDocument/didOpen file://" + path
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+
+		var initializes []int
+		for i, line := range lines {
+			if line == "initialize" {
+				initializes = append(initializes, i)
+			}
+		}
+		return len(initializes) == 2 && slices.Contains(lines[initializes[1]:], didOpen)
+	}, 5*time.Second, 10*time.Millisecond, "the restarted server was not sent the open file")
+}
+
+func TestSupervisorGivesUpAfterMaxRestarts(t *testing.T) {
+	dir := t.TempDir()
+	client, _ := startFakeServer(t, dir)
+
+	// New server processes fail to start
+	client.command = filepath.Join(dir, "missing-server")
+
+	config := &SupervisorConfig{
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		MaxRestarts:    3,
+	}
+	supervisor := NewSupervisorWithConfig(client, dir, config)
+
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		supervisor.Run(context.Background())
+		close(done)
+	}()
+
+	killServer(t, client)
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("supervisor kept restarting")
+	}
+
+	// The backoff doubles after each attempt: 20ms, 40ms, 80ms
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+}
@@ -4,11 +4,14 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/isaacphi/mcp-language-server/internal/logging"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
 // Create component-specific loggers
@@ -16,6 +19,14 @@ var lspLogger = logging.NewLogger(logging.LSP)
 var wireLogger = logging.NewLogger(logging.LSPWire)
 var processLogger = logging.NewLogger(logging.LSPProcess)
 
+// ErrRequestTimeout is returned by Call when the server does not answer a
+// request within the configured timeout
+var ErrRequestTimeout = errors.New("request timed out")
+
+// ErrServerExited is returned for requests that cannot complete because the
+// language server process exited or closed its output
+var ErrServerExited = errors.New("language server exited")
+
 // WriteMessage writes an LSP message to the given writer
 func WriteMessage(w io.Writer, msg *Message) error {
 	data, err := json.Marshal(msg)
@@ -94,10 +105,11 @@ func ReadMessage(r *bufio.Reader) (*Message, error) {
 	return &msg, nil
 }
 
-// handleMessages reads and dispatches messages in a loop
-func (c *Client) handleMessages() {
+// handleMessages reads and dispatches messages in a loop until the server's
+// output can no longer be read, at which point exited is closed
+func (c *Client) handleMessages(stdout *bufio.Reader, exited chan struct{}) {
 	for {
-		msg, err := ReadMessage(c.stdout)
+		msg, err := ReadMessage(stdout)
 		if err != nil {
 			// Check if this is due to normal shutdown (EOF when closing connection)
 			if strings.Contains(err.Error(), "EOF") {
@@ -105,6 +117,14 @@ func (c *Client) handleMessages() {
 			} else {
 				lspLogger.Error("Error reading message: %v", err)
 			}
+
+			// Fail any requests still waiting on this process
+			c.procMu.Lock()
+			if c.exited == exited {
+				c.exitErr = err
+			}
+			c.procMu.Unlock()
+			close(exited)
 			return
 		}
 
@@ -150,7 +170,7 @@ func (c *Client) handleMessages() {
 			}
 
 			// Send response back to server
-			if err := WriteMessage(c.stdin, response); err != nil {
+			if err := c.writeMessage(response); err != nil {
 				lspLogger.Error("Error sending response to server: %v", err)
 			}
 
@@ -191,10 +211,38 @@ func (c *Client) handleMessages() {
 	}
 }
 
-// Call makes a request and waits for the response
+// writeMessage serializes writes to the server so concurrent requests,
+// notifications and responses don't interleave on the wire
+func (c *Client) writeMessage(msg *Message) error {
+	c.procMu.RLock()
+	stdin := c.stdin
+	exited := c.exited
+	c.procMu.RUnlock()
+
+	select {
+	case <-exited:
+		return ErrServerExited
+	default:
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return WriteMessage(stdin, msg)
+}
+
+// Call makes a request and waits for the response. The request is abandoned
+// when ctx is done or the timeout configured for the method elapses, in which
+// case the server is sent a $/cancelRequest notification.
 func (c *Client) Call(ctx context.Context, method string, params any, result any) error {
 	id := c.nextID.Add(1)
 
+	timeout := c.requestTimeout(method)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	lspLogger.Debug("Making call: method=%s id=%v", method, id)
 
 	msg, err := NewRequest(id, method, params)
@@ -216,15 +264,32 @@ func (c *Client) Call(ctx context.Context, method string, params any, result any
 		c.handlersMu.Unlock()
 	}()
 
+	// Requests sent to this process fail if it exits before answering
+	exited := c.Exited()
+
 	// Send request
-	if err := WriteMessage(c.stdin, msg); err != nil {
+	start := time.Now()
+	if err := c.writeMessage(msg); err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 
 	lspLogger.Debug("Waiting for response to request ID: %v", msg.ID)
 
 	// Wait for response
-	resp := <-ch
+	var resp *Message
+	select {
+	case resp = <-ch:
+	case <-exited:
+		return fmt.Errorf("%s failed: %w", method, ErrServerExited)
+	case <-ctx.Done():
+		c.cancelRequest(msg.ID)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			elapsed := time.Since(start).Round(time.Millisecond)
+			lspLogger.Warn("Request timed out: method=%s id=%v after %s", method, msg.ID, elapsed)
+			return fmt.Errorf("%w: %s did not respond within %s", ErrRequestTimeout, method, elapsed)
+		}
+		return fmt.Errorf("request %s cancelled: %w", method, ctx.Err())
+	}
 
 	lspLogger.Debug("Received response for request ID: %v", msg.ID)
 
@@ -258,12 +323,23 @@ func (c *Client) Notify(ctx context.Context, method string, params any) error {
 		return fmt.Errorf("failed to create notification: %w", err)
 	}
 
-	if err := WriteMessage(c.stdin, msg); err != nil {
+	if err := c.writeMessage(msg); err != nil {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
 	return nil
 }
 
+// cancelRequest tells the server that we are no longer waiting for the
+// response to the request with the given ID
+func (c *Client) cancelRequest(id *MessageID) {
+	lspLogger.Debug("Cancelling request ID: %v", id)
+
+	// The caller's context is already done, so don't tie the notification to it
+	if err := c.Notify(context.Background(), "$/cancelRequest", protocol.CancelParams{ID: id.Value}); err != nil {
+		lspLogger.Error("Failed to cancel request %v: %v", id, err)
+	}
+}
+
 type NotificationHandler func(params json.RawMessage)
 type ServerRequestHandler func(params json.RawMessage) (any, error)
@@ -0,0 +1,153 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPipeClient returns a client wired to in-memory pipes instead of a
+// language server process, along with the server side of the pipes
+func newPipeClient(t *testing.T) (*Client, *bufio.Reader, io.WriteCloser) {
+	t.Helper()
+
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+
+	client := &Client{
+		config:                DefaultClientConfig(),
+		settings:              NewSettingsStore(nil),
+		stdin:                 clientOut,
+		stdout:                bufio.NewReader(clientIn),
+		handlers:              make(map[string]chan *Message),
+		notificationHandlers:  make(map[string]NotificationHandler),
+		serverRequestHandlers: make(map[string]ServerRequestHandler),
+		diagnostics:           make(map[protocol.DocumentUri]*fileDiagnostics),
+		diagnosticsChanged:    make(chan struct{}),
+		openFiles:             make(map[string]*OpenFileInfo),
+		defaultTimeout:        DefaultRequestTimeout,
+		methodTimeouts:        make(map[string]time.Duration),
+		exited:                make(chan struct{}),
+		progress:              make(map[string]*ProgressInfo),
+		progressEnded:         make(map[string]bool),
+		progressChanged:       make(chan struct{}),
+		progressChangedAt:     time.Now(),
+		readiness:             DefaultReadinessConfig(),
+	}
+	go client.handleMessages(client.stdout, client.exited)
+
+	t.Cleanup(func() {
+		_ = serverOut.Close()
+		_ = clientOut.Close()
+	})
+
+	return client, bufio.NewReader(serverIn), serverOut
+}
+
+func TestCallTimeoutSendsCancel(t *testing.T) {
+	client, serverReader, _ := newPipeClient(t)
+	client.SetMethodTimeout("textDocument/hover", 50*time.Millisecond)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- client.Call(context.Background(), "textDocument/hover", struct{}{}, nil)
+	}()
+
+	// The server reads the request but never answers it
+	req, err := ReadMessage(serverReader)
+	require.NoError(t, err)
+	assert.Equal(t, "textDocument/hover", req.Method)
+
+	cancel, err := ReadMessage(serverReader)
+	require.NoError(t, err)
+	assert.Equal(t, "$/cancelRequest", cancel.Method)
+
+	var params protocol.CancelParams
+	require.NoError(t, json.Unmarshal(cancel.Params, &params))
+	assert.EqualValues(t, req.ID.Value, params.ID)
+
+	err = <-errCh
+	assert.True(t, errors.Is(err, ErrRequestTimeout), "expected timeout error, got %v", err)
+
+	client.handlersMu.RLock()
+	defer client.handlersMu.RUnlock()
+	assert.Empty(t, client.handlers)
+}
+
+func TestCallContextCancelled(t *testing.T) {
+	client, serverReader, _ := newPipeClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- client.Call(ctx, "workspace/symbol", struct{}{}, nil)
+	}()
+
+	_, err := ReadMessage(serverReader)
+	require.NoError(t, err)
+	cancel()
+
+	cancelMsg, err := ReadMessage(serverReader)
+	require.NoError(t, err)
+	assert.Equal(t, "$/cancelRequest", cancelMsg.Method)
+
+	err = <-errCh
+	assert.True(t, errors.Is(err, context.Canceled), "expected cancellation error, got %v", err)
+	assert.False(t, errors.Is(err, ErrRequestTimeout))
+}
+
+func TestCallReceivesResponse(t *testing.T) {
+	client, serverReader, serverWriter := newPipeClient(t)
+
+	resultCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		var result string
+		err := client.Call(context.Background(), "custom/echo", struct{}{}, &result)
+		resultCh <- result
+		errCh <- err
+	}()
+
+	req, err := ReadMessage(serverReader)
+	require.NoError(t, err)
+
+	require.NoError(t, WriteMessage(serverWriter, &Message{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  json.RawMessage(`"hello"`),
+	}))
+
+	require.NoError(t, <-errCh)
+	assert.Equal(t, "hello", <-resultCh)
+}
+
+func TestCallFailsWhenServerExits(t *testing.T) {
+	client, serverReader, serverWriter := newPipeClient(t)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- client.Call(context.Background(), "textDocument/definition", struct{}{}, nil)
+	}()
+
+	_, err := ReadMessage(serverReader)
+	require.NoError(t, err)
+
+	// Simulate the server process dying
+	require.NoError(t, serverWriter.Close())
+
+	err = <-errCh
+	assert.True(t, errors.Is(err, ErrServerExited), "expected server exited error, got %v", err)
+
+	// Later requests fail immediately instead of writing into a dead pipe
+	err = client.Call(context.Background(), "textDocument/hover", struct{}{}, nil)
+	assert.True(t, errors.Is(err, ErrServerExited), "expected server exited error, got %v", err)
+	assert.Error(t, client.ExitError())
+}
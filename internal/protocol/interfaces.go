@@ -1,6 +1,10 @@
 package protocol
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 // TextEditResult is an interface for types that represent workspace symbols
 type WorkspaceSymbolResult interface {
@@ -115,3 +119,179 @@ func (e Or_TextDocumentEdit_edits_Elem) AsTextEdit() (TextEdit, error) {
 		return TextEdit{}, fmt.Errorf("unknown text edit type: %T", e.Value)
 	}
 }
+
+// Locations converts the Value to a slice of Location
+func (d Or_Definition) Locations() ([]Location, error) {
+	switch v := d.Value.(type) {
+	case nil:
+		return nil, nil
+	case Location:
+		return []Location{v}, nil
+	case []Location:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unknown definition type: %T", v)
+	}
+}
+
+// linkLocations converts location links to the locations of their targets.
+// The selection range is used since it covers the target's name.
+func linkLocations(links []LocationLink) []Location {
+	locations := make([]Location, len(links))
+	for i, link := range links {
+		locations[i] = Location{URI: link.TargetURI, Range: link.TargetSelectionRange}
+	}
+	return locations
+}
+
+// Locations converts the Value to a slice of Location
+func (r Or_Result_textDocument_implementation) Locations() ([]Location, error) {
+	switch v := r.Value.(type) {
+	case nil:
+		return nil, nil
+	case Definition:
+		return v.Locations()
+	case []DefinitionLink:
+		return linkLocations(v), nil
+	default:
+		return nil, fmt.Errorf("unknown implementation type: %T", v)
+	}
+}
+
+// Locations converts the Value to a slice of Location
+func (r Or_Result_textDocument_definition) Locations() ([]Location, error) {
+	switch v := r.Value.(type) {
+	case nil:
+		return nil, nil
+	case Definition:
+		return v.Locations()
+	case []DefinitionLink:
+		return linkLocations(v), nil
+	default:
+		return nil, fmt.Errorf("unknown definition type: %T", v)
+	}
+}
+
+// Locations converts the Value to a slice of Location
+func (r Or_Result_textDocument_typeDefinition) Locations() ([]Location, error) {
+	switch v := r.Value.(type) {
+	case nil:
+		return nil, nil
+	case Definition:
+		return v.Locations()
+	case []DefinitionLink:
+		return linkLocations(v), nil
+	default:
+		return nil, fmt.Errorf("unknown type definition type: %T", v)
+	}
+}
+
+// Locations converts the Value to a slice of Location
+func (d Or_Declaration) Locations() ([]Location, error) {
+	switch v := d.Value.(type) {
+	case nil:
+		return nil, nil
+	case Location:
+		return []Location{v}, nil
+	case []Location:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unknown declaration type: %T", v)
+	}
+}
+
+// Locations converts the Value to a slice of Location
+func (r Or_Result_textDocument_declaration) Locations() ([]Location, error) {
+	switch v := r.Value.(type) {
+	case nil:
+		return nil, nil
+	case Declaration:
+		return v.Locations()
+	case []DeclarationLink:
+		return linkLocations(v), nil
+	default:
+		return nil, fmt.Errorf("unknown declaration type: %T", v)
+	}
+}
+
+// Items converts the Value to a slice of CompletionItem and reports whether
+// the list is incomplete
+func (r Or_Result_textDocument_completion) Items() ([]CompletionItem, bool, error) {
+	switch v := r.Value.(type) {
+	case nil:
+		return nil, false, nil
+	case CompletionList:
+		return v.Items, v.IsIncomplete, nil
+	case []CompletionItem:
+		return v, false, nil
+	default:
+		return nil, false, fmt.Errorf("unknown completion type: %T", v)
+	}
+}
+
+// markupText returns the text of a string or MarkupContent value
+func markupText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case MarkupContent:
+		return v.Value
+	default:
+		return ""
+	}
+}
+
+// Text returns the documentation's text
+func (d *Or_CompletionItem_documentation) Text() string {
+	if d == nil {
+		return ""
+	}
+	return markupText(d.Value)
+}
+
+// Text returns the documentation's text
+func (d *Or_SignatureInformation_documentation) Text() string {
+	if d == nil {
+		return ""
+	}
+	return markupText(d.Value)
+}
+
+// Text returns the documentation's text
+func (d *Or_ParameterInformation_documentation) Text() string {
+	if d == nil {
+		return ""
+	}
+	return markupText(d.Value)
+}
+
+// Text returns the label's text, joining the values of label parts
+func (l Or_InlayHint_label) Text() string {
+	switch v := l.Value.(type) {
+	case string:
+		return v
+	case []InlayHintLabelPart:
+		var text strings.Builder
+		for _, part := range v {
+			text.WriteString(part.Value)
+		}
+		return text.String()
+	default:
+		return ""
+	}
+}
+
+// MarshalJSON encodes the label offsets as the [start, end] pair servers send
+func (t Tuple_ParameterInformation_label_Item1) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]uint32{t.Fld0, t.Fld1})
+}
+
+// UnmarshalJSON decodes label offsets from a [start, end] pair
+func (t *Tuple_ParameterInformation_label_Item1) UnmarshalJSON(data []byte) error {
+	var offsets [2]uint32
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		return err
+	}
+	t.Fld0, t.Fld1 = offsets[0], offsets[1]
+	return nil
+}
@@ -0,0 +1,269 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// batchHoverWorkers is the number of hover requests sent at once
+const batchHoverWorkers = 8
+
+// maxBatchHoverIdentifiers is the number of identifiers hovered in one call
+const maxBatchHoverIdentifiers = 300
+
+// maxGlossaryCodeLines is the number of lines of a hover's code kept in a
+// glossary entry
+const maxGlossaryCodeLines = 8
+
+// maxGlossaryDocLength is the length a glossary entry's documentation is cut to
+const maxGlossaryDocLength = 200
+
+// identifier is an identifier's name and 0-indexed position
+type identifier struct {
+	name     string
+	position protocol.Position
+}
+
+// glossaryEntry is hover text shared by one or more identifiers
+type glossaryEntry struct {
+	text  string
+	names []string
+	lines []int
+}
+
+// GetHoverGlossary hovers over every identifier in a range of lines and
+// returns the hover text once for each distinct result, with the names and
+// lines it was found for
+func GetHoverGlossary(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine int) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	if startLine < 1 || startLine > len(lines) {
+		return "", fmt.Errorf("start line %d is outside the file (%d lines)", startLine, len(lines))
+	}
+	if endLine < startLine {
+		endLine = startLine
+	}
+	endLine = min(endLine, len(lines))
+
+	// Semantic tokens leave out keywords, comments and strings, so the text
+	// is only scanned for servers without them
+	var identifiers []identifier
+	if tokens, err := semanticTokensIn(ctx, client, filePath, lines, startLine, endLine); err == nil {
+		identifiers = tokenIdentifiers(lines, tokens)
+	} else {
+		toolsLogger.Debug("scanning for identifiers instead of using semantic tokens: %v", err)
+		identifiers = scanIdentifiers(lines[startLine-1:endLine], startLine-1)
+	}
+	truncated := len(identifiers) > maxBatchHoverIdentifiers
+	if truncated {
+		identifiers = identifiers[:maxBatchHoverIdentifiers]
+	}
+
+	uri := protocol.DocumentUri("file://" + filePath)
+	texts, err := hoverAll(ctx, client, uri, identifiers)
+	if err != nil {
+		return "", err
+	}
+
+	entries := buildGlossary(identifiers, texts)
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Hover glossary for %s lines %d-%d, %d entries:\n", filepath.Base(filePath), startLine, endLine, len(entries))
+	for _, entry := range entries {
+		lineRefs := make([]string, len(entry.lines))
+		for i, line := range entry.lines {
+			lineRefs[i] = fmt.Sprintf("L%d", line)
+		}
+		fmt.Fprintf(&result, "\n%s (%s)\n", strings.Join(entry.names, ", "), strings.Join(lineRefs, ", "))
+		for _, line := range strings.Split(entry.text, "\n") {
+			result.WriteString("    " + line + "\n")
+		}
+	}
+	if truncated {
+		fmt.Fprintf(&result, "\nOnly the first %d identifiers were hovered. Use a smaller range to see the rest.\n", maxBatchHoverIdentifiers)
+	}
+	return result.String(), nil
+}
+
+// hoverAll sends hover requests for identifiers from a bounded pool of
+// workers. The compacted text for each identifier is returned in the same
+// order, empty where the server had nothing to show. An error is only
+// returned if every request failed.
+func hoverAll(ctx context.Context, client *lsp.Client, uri protocol.DocumentUri, identifiers []identifier) ([]string, error) {
+	texts := make([]string, len(identifiers))
+	errs := make([]error, len(identifiers))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(batchHoverWorkers, len(identifiers)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				hover, err := client.Hover(ctx, protocol.HoverParams{
+					TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+						TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+						Position:     identifiers[i].position,
+					},
+				})
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				texts[i] = compactHover(hover.Contents.Value)
+			}
+		}()
+	}
+
+	for i := range identifiers {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			toolsLogger.Debug("failed to hover %s at %d:%d: %v", identifiers[i].name,
+				identifiers[i].position.Line+1, identifiers[i].position.Character+1, err)
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if failed > 0 && failed == len(identifiers) {
+		return nil, fmt.Errorf("failed to get hover information: %v", errs[0])
+	}
+	return texts, nil
+}
+
+// scanIdentifiers finds the identifiers in lines, the first of which is
+// firstLine (0-indexed). Words in comments and strings are included; the
+// server has no hover for them.
+func scanIdentifiers(lines []string, firstLine int) []identifier {
+	var identifiers []identifier
+	for i, line := range lines {
+		start := -1
+		for j, r := range line + " " {
+			isPart := r == '_' || unicode.IsLetter(r) || (start >= 0 && unicode.IsDigit(r))
+			if isPart && start < 0 {
+				start = j
+			} else if !isPart && start >= 0 {
+				identifiers = append(identifiers, identifier{
+					name:     line[start:j],
+					position: protocol.Position{Line: uint32(firstLine + i), Character: utf16Offset(line, start)},
+				})
+				start = -1
+			}
+		}
+	}
+	return identifiers
+}
+
+// tokenIdentifiers returns the identifiers among semantic tokens
+func tokenIdentifiers(lines []string, tokens []semanticToken) []identifier {
+	var identifiers []identifier
+	for _, token := range tokens {
+		if slices.Contains(nonIdentifierTokenTypes, token.tokenType) {
+			continue
+		}
+		if name := tokenText(lines, token); name != "" {
+			identifiers = append(identifiers, identifier{
+				name:     name,
+				position: protocol.Position{Line: token.line, Character: token.start},
+			})
+		}
+	}
+	return identifiers
+}
+
+// buildGlossary groups identifiers by their hover text, in the order the
+// text is first found
+func buildGlossary(identifiers []identifier, texts []string) []*glossaryEntry {
+	var entries []*glossaryEntry
+	byText := make(map[string]*glossaryEntry)
+	for i, id := range identifiers {
+		text := texts[i]
+		if text == "" {
+			continue
+		}
+		entry, ok := byText[text]
+		if !ok {
+			entry = &glossaryEntry{text: text}
+			byText[text] = entry
+			entries = append(entries, entry)
+		}
+		if !slices.Contains(entry.names, id.name) {
+			entry.names = append(entry.names, id.name)
+		}
+		line := int(id.position.Line) + 1
+		if !slices.Contains(entry.lines, line) {
+			entry.lines = append(entry.lines, line)
+		}
+	}
+	return entries
+}
+
+// compactHover shortens hover text to its code, such as a declaration, and
+// the first paragraph of the rest
+func compactHover(text string) string {
+	var code, rest []string
+	inFence := false
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			code = append(code, line)
+		} else {
+			rest = append(rest, line)
+		}
+	}
+
+	// Plain text hovers have no code block, so their first paragraph is
+	// usually the declaration
+	if len(code) == 0 {
+		return firstParagraph(strings.Join(rest, "\n"))
+	}
+
+	code = slices.DeleteFunc(code, func(line string) bool { return strings.TrimSpace(line) == "" })
+	if len(code) > maxGlossaryCodeLines {
+		code = append(code[:maxGlossaryCodeLines], "...")
+	}
+	compact := strings.Join(code, "\n")
+	if doc := firstParagraph(strings.Join(rest, "\n")); doc != "" {
+		if len(doc) > maxGlossaryDocLength {
+			// Cut at the start of a character
+			cut := maxGlossaryDocLength
+			for !utf8.RuneStart(doc[cut]) {
+				cut--
+			}
+			doc = doc[:cut] + "..."
+		}
+		compact += "\n" + doc
+	}
+	return compact
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanIdentifiers(t *testing.T) {
+	identifiers := scanIdentifiers([]string{
+		"\tcount := compute(5, v2)",
+		"",
+		"\treturn café_1",
+		"\tnäme := größe",
+	}, 9)
+
+	var got []string
+	for _, id := range identifiers {
+		got = append(got, id.name)
+	}
+	assert.Equal(t, []string{"count", "compute", "v2", "return", "café_1", "näme", "größe"}, got)
+	assert.Equal(t, protocol.Position{Line: 9, Character: 10}, identifiers[1].position)
+	assert.Equal(t, protocol.Position{Line: 11, Character: 8}, identifiers[4].position)
+	// Characters count UTF-16 units, not bytes
+	assert.Equal(t, protocol.Position{Line: 12, Character: 9}, identifiers[6].position)
+}
+
+func TestCompactHover(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "Code block and documentation",
+			text: "```go\nfunc Println(a ...any) (n int, err error)\n```\n\nPrintln formats using the default formats\nfor its operands.\n\nSpaces are always added.\n\n[`fmt.Println` on pkg.go.dev](https://pkg.go.dev/fmt#Println)",
+			want: "func Println(a ...any) (n int, err error)\nPrintln formats using the default formats for its operands.",
+		},
+		{
+			name: "Long code block",
+			text: "```go\ntype T struct {\n\tA int\n\tB int\n\tC int\n\n\tD int\n\tE int\n\tF int\n\tG int\n}\n```",
+			want: "type T struct {\n\tA int\n\tB int\n\tC int\n\tD int\n\tE int\n\tF int\n\tG int\n...",
+		},
+		{
+			name: "Plain text",
+			text: "(variable) count: number\n\nThe number of items.",
+			want: "(variable) count: number",
+		},
+		{
+			name: "Long documentation",
+			text: "```go\nvar x int\n```\n\n" + strings.Repeat("a", maxGlossaryDocLength-1) + "éé",
+			want: "var x int\n" + strings.Repeat("a", maxGlossaryDocLength-1) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, compactHover(tt.text))
+		})
+	}
+}
+
+func TestBuildGlossary(t *testing.T) {
+	at := func(name string, line uint32) identifier {
+		return identifier{name: name, position: protocol.Position{Line: line}}
+	}
+	identifiers := []identifier{at("err", 0), at("fmt", 1), at("err", 2), at("if", 2), at("e", 3)}
+	texts := []string{"var err error", "package fmt", "var err error", "", "var err error"}
+
+	entries := buildGlossary(identifiers, texts)
+	assert.Equal(t, []*glossaryEntry{
+		{text: "var err error", names: []string{"err", "e"}, lines: []int{1, 3, 4}},
+		{text: "package fmt", names: []string{"fmt"}, lines: []int{2}},
+	}, entries)
+}
@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+const (
+	// DefaultCallHierarchyDepth is how many levels of calls are shown by default
+	DefaultCallHierarchyDepth = 1
+
+	// maxCallHierarchyDepth caps the requested depth, since call trees grow
+	// quickly
+	maxCallHierarchyDepth = 5
+
+	// maxCallHierarchyItems stops expanding the tree once this many calls have
+	// been listed
+	maxCallHierarchyItems = 200
+
+	// maxCallSites is the number of call site snippets shown per call
+	maxCallSites = 3
+)
+
+// Call hierarchy directions
+const (
+	CallsIncoming = "incoming"
+	CallsOutgoing = "outgoing"
+	CallsBoth     = "both"
+)
+
+// GetCallHierarchy shows the callers and/or callees of the function at a
+// position, recursively up to depth levels
+func GetCallHierarchy(ctx context.Context, client *lsp.Client, filePath string, line, column int, direction string, depth int) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	position := protocol.Position{
+		Line:      uint32(line - 1),
+		Character: uint32(column - 1),
+	}
+	uri := protocol.DocumentUri("file://" + filePath)
+
+	text, found, err := callHierarchyAt(ctx, client, uri, position, direction, depth)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("No function or method found at %s:%d:%d", filePath, line, column) + indexingNotice(client), nil
+	}
+	return text, nil
+}
+
+// GetCallHierarchyForSymbol shows the callers and/or callees of every
+// function or method with the given name
+func GetCallHierarchyForSymbol(ctx context.Context, clients []*lsp.Client, symbolName string, direction string, depth int) (string, error) {
+	positions, err := findSymbolPositions(ctx, clients, symbolName)
+	if err != nil {
+		return "", err
+	}
+
+	var sections []string
+	for _, pos := range positions {
+		text, found, err := callHierarchyAt(ctx, pos.client, pos.uri, pos.position, direction, depth)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			sections = append(sections, text)
+		}
+	}
+
+	if len(sections) == 0 {
+		return fmt.Sprintf("%s not found", symbolName) + indexingNotice(clients...), nil
+	}
+	return strings.Join(sections, "\n"), nil
+}
+
+// callHierarchyAt prepares the call hierarchy at a position and renders it.
+// found is false if there is no callable symbol at the position.
+func callHierarchyAt(ctx context.Context, client *lsp.Client, uri protocol.DocumentUri, position protocol.Position, direction string, depth int) (string, bool, error) {
+	directions, depth, err := hierarchyDirections(direction, CallsIncoming, [2]string{CallsIncoming, CallsOutgoing},
+		depth, DefaultCallHierarchyDepth, maxCallHierarchyDepth)
+	if err != nil {
+		return "", false, err
+	}
+
+	items, err := client.PrepareCallHierarchy(ctx, protocol.CallHierarchyPrepareParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     position,
+		},
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to prepare call hierarchy: %v", err)
+	}
+	if len(items) == 0 {
+		return "", false, nil
+	}
+
+	workspaceDir, err := os.Getwd()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get workspace directory: %v", err)
+	}
+
+	roots := make([]callSite, len(items))
+	for i, item := range items {
+		roots[i] = callSite{item: item}
+	}
+	lines := make(sourceLines)
+	text, err := renderHierarchy(roots, directions, func(dir string) *treeWriter[callSite] {
+		return newCallHierarchyWriter(dir, callFetcher(ctx, client, dir), depth, workspaceDir, lines)
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return text, true, nil
+}
+
+// newCallHierarchyWriter returns a writer for a call tree in one direction.
+// calls fetches the calls to or from an item, and lines caches the files
+// that call sites are shown from.
+func newCallHierarchyWriter(direction string, calls func(protocol.CallHierarchyItem) ([]callSite, error), maxDepth int, workspaceDir string, lines sourceLines) *treeWriter[callSite] {
+	w := &treeWriter[callSite]{
+		header: "Incoming calls to ",
+		related: func(site callSite) ([]callSite, error) {
+			return calls(site.item)
+		},
+		item:         func(site callSite) hierarchyItem { return callHierarchyItem(site.item) },
+		details:      lines.callSites,
+		prefix:       "<- ",
+		cycleNote:    " (recursive)",
+		expandedNote: " (calls listed above)",
+		noun:         "calls",
+		maxItems:     maxCallHierarchyItems,
+		maxDepth:     maxDepth,
+		workspaceDir: workspaceDir,
+	}
+	if direction == CallsOutgoing {
+		w.header = "Outgoing calls from "
+		w.prefix = "-> "
+	}
+	return w
+}
+
+// callSite is a call to or from an item along with where the calls are made
+type callSite struct {
+	item       protocol.CallHierarchyItem
+	siteURI    protocol.DocumentUri
+	siteRanges []protocol.Range
+}
+
+// callFetcher returns a function that asks the server for the calls to or
+// from an item
+func callFetcher(ctx context.Context, client *lsp.Client, direction string) func(protocol.CallHierarchyItem) ([]callSite, error) {
+	if direction == CallsIncoming {
+		return func(item protocol.CallHierarchyItem) ([]callSite, error) {
+			calls, err := client.IncomingCalls(ctx, protocol.CallHierarchyIncomingCallsParams{Item: item})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get incoming calls: %v", err)
+			}
+			var sites []callSite
+			for _, call := range calls {
+				// Call sites are in the caller
+				sites = append(sites, callSite{item: call.From, siteURI: call.From.URI, siteRanges: call.FromRanges})
+			}
+			return sites, nil
+		}
+	}
+	return func(item protocol.CallHierarchyItem) ([]callSite, error) {
+		calls, err := client.OutgoingCalls(ctx, protocol.CallHierarchyOutgoingCallsParams{Item: item})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get outgoing calls: %v", err)
+		}
+		var sites []callSite
+		for _, call := range calls {
+			// Call sites are in the item making the calls
+			sites = append(sites, callSite{item: call.To, siteURI: item.URI, siteRanges: call.FromRanges})
+		}
+		return sites, nil
+	}
+}
+
+// callHierarchyItem returns the fields of a call hierarchy item shown in the tree
+func callHierarchyItem(item protocol.CallHierarchyItem) hierarchyItem {
+	return hierarchyItem{name: item.Name, kind: item.Kind, detail: item.Detail, uri: item.URI, selection: item.SelectionRange}
+}
+
+// sourceLines caches the lines of files by URI
+type sourceLines map[protocol.DocumentUri][]string
+
+func (s sourceLines) get(uri protocol.DocumentUri) []string {
+	if lines, ok := s[uri]; ok {
+		return lines
+	}
+	var lines []string
+	if content, err := os.ReadFile(uri.Path()); err == nil {
+		lines = strings.Split(string(content), "\n")
+	}
+	s[uri] = lines
+	return lines
+}
+
+// callSites returns the source lines where the calls are made
+func (s sourceLines) callSites(site callSite) []string {
+	lines := s.get(site.siteURI)
+	seen := make(map[uint32]bool)
+	var shown []string
+	for i, rng := range site.siteRanges {
+		line := rng.Start.Line
+		if seen[line] || int(line) >= len(lines) {
+			continue
+		}
+		seen[line] = true
+		if len(shown) == maxCallSites {
+			return append(shown, fmt.Sprintf("... %d more call sites", len(site.siteRanges)-i))
+		}
+		shown = append(shown, fmt.Sprintf("L%d: %s", line+1, strings.TrimSpace(lines[line])))
+	}
+	return shown
+}
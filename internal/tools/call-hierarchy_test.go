@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallHierarchyWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"+
+		"func main() {\n"+
+		"\tserve()\n"+
+		"}\n"+
+		"func serve() {\n"+
+		"\thandle()\n"+
+		"\thandle()\n"+
+		"}\n"+
+		"func handle() {\n"+
+		"\tserve()\n"+
+		"}\n"), 0644))
+	uri := protocol.DocumentUri("file://" + path)
+
+	item := func(name string, line uint32) protocol.CallHierarchyItem {
+		return protocol.CallHierarchyItem{
+			Name:           name,
+			Kind:           protocol.Function,
+			URI:            uri,
+			Range:          lineRange(line, line+2),
+			SelectionRange: lineRange(line, line),
+		}
+	}
+	mainItem, serveItem, handleItem := item("main", 1), item("serve", 4), item("handle", 8)
+	site := func(line uint32) protocol.Range { return lineRange(line, line) }
+
+	// serve and handle call each other
+	incoming := map[string][]callSite{
+		"serve": {
+			{item: mainItem, siteURI: uri, siteRanges: []protocol.Range{site(2)}},
+			{item: handleItem, siteURI: uri, siteRanges: []protocol.Range{site(9)}},
+		},
+		"handle": {
+			{item: serveItem, siteURI: uri, siteRanges: []protocol.Range{site(5), site(6)}},
+		},
+	}
+	fetch := func(item protocol.CallHierarchyItem) ([]callSite, error) {
+		return incoming[item.Name], nil
+	}
+
+	t.Run("One level", func(t *testing.T) {
+		w := newCallHierarchyWriter(CallsIncoming, fetch, 1, dir, make(sourceLines))
+		require.NoError(t, w.write(callSite{item: handleItem}))
+		assert.Equal(t, "Incoming calls to Function handle - main.go:9\n"+
+			"  <- Function serve - main.go:5\n"+
+			"       L6: handle()\n"+
+			"       L7: handle()\n", w.output.String())
+	})
+
+	t.Run("Cycles are not followed", func(t *testing.T) {
+		w := newCallHierarchyWriter(CallsIncoming, fetch, 5, dir, make(sourceLines))
+		require.NoError(t, w.write(callSite{item: handleItem}))
+		assert.Equal(t, "Incoming calls to Function handle - main.go:9\n"+
+			"  <- Function serve - main.go:5\n"+
+			"       L6: handle()\n"+
+			"       L7: handle()\n"+
+			"    <- Function main - main.go:2\n"+
+			"         L3: serve()\n"+
+			"    <- Function handle - main.go:9 (recursive)\n"+
+			"         L10: serve()\n", w.output.String())
+	})
+
+	t.Run("No calls", func(t *testing.T) {
+		w := newCallHierarchyWriter(CallsIncoming, fetch, 1, dir, make(sourceLines))
+		require.NoError(t, w.write(callSite{item: mainItem}))
+		assert.Equal(t, "Incoming calls to Function main - main.go:2\n  (none)\n", w.output.String())
+	})
+}
+
+func TestCallHierarchyWriterSkipsExpandedItems(t *testing.T) {
+	// a and b both call shared, which calls leaf
+	item := func(name string, line uint32) protocol.CallHierarchyItem {
+		return protocol.CallHierarchyItem{
+			Name:           name,
+			Kind:           protocol.Function,
+			URI:            "file:///src/calls.go",
+			SelectionRange: lineRange(line, line),
+		}
+	}
+	outgoing := map[string][]protocol.CallHierarchyItem{
+		"root":   {item("a", 1), item("b", 2)},
+		"a":      {item("shared", 3)},
+		"b":      {item("shared", 3)},
+		"shared": {item("leaf", 4)},
+	}
+	fetch := func(parent protocol.CallHierarchyItem) ([]callSite, error) {
+		var sites []callSite
+		for _, callee := range outgoing[parent.Name] {
+			sites = append(sites, callSite{item: callee, siteURI: parent.URI})
+		}
+		return sites, nil
+	}
+
+	w := newCallHierarchyWriter(CallsOutgoing, fetch, 5, "/src", make(sourceLines))
+	require.NoError(t, w.write(callSite{item: item("root", 0)}))
+	assert.Equal(t, "Outgoing calls from Function root - calls.go:1\n"+
+		"  -> Function a - calls.go:2\n"+
+		"    -> Function shared - calls.go:4\n"+
+		"      -> Function leaf - calls.go:5\n"+
+		"  -> Function b - calls.go:3\n"+
+		"    -> Function shared - calls.go:4 (calls listed above)\n", w.output.String())
+}
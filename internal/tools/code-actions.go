@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// ListCodeActions lists the code actions, such as quick fixes and
+// refactorings, available for lines of a file. Diagnostics on those lines are
+// sent to the server so it can offer fixes for them. kinds limits the actions
+// to kinds such as "quickfix" or "refactor.extract".
+func ListCodeActions(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine int, kinds []string) (string, error) {
+	actions, err := getCodeActions(ctx, client, filePath, startLine, endLine, kinds)
+	if err != nil {
+		return "", err
+	}
+
+	lines := fmt.Sprintf("L%d", startLine)
+	if endLine > startLine {
+		lines += fmt.Sprintf("-L%d", endLine)
+	}
+	if len(actions) == 0 {
+		return fmt.Sprintf("No code actions available for %s %s", filepath.Base(filePath), lines), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Code actions for %s %s:\n", filepath.Base(filePath), lines))
+	for i, action := range actions {
+		output.WriteString(fmt.Sprintf("%d. %s", i+1, action.Title))
+		if action.Kind != "" {
+			output.WriteString(fmt.Sprintf(" [%s]", action.Kind))
+		}
+		if action.IsPreferred {
+			output.WriteString(" (preferred)")
+		}
+		if action.Disabled != nil {
+			output.WriteString(fmt.Sprintf(" (disabled: %s)", action.Disabled.Reason))
+		}
+		output.WriteString("\n")
+		for _, diag := range action.Diagnostics {
+			output.WriteString(fmt.Sprintf("   Fixes L%d: %s\n", diag.Range.Start.Line+1, diag.Message))
+		}
+	}
+	return output.String(), nil
+}
+
+// ApplyCodeAction applies one of the code actions listed by ListCodeActions
+// for the same lines, chosen by title or by its 1-based index. Edits the
+// server computes lazily are resolved first, and any command attached to the
+// action is executed after its edit is applied.
+func ApplyCodeAction(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine int, kinds []string, index int, title string) (string, error) {
+	actions, err := getCodeActions(ctx, client, filePath, startLine, endLine, kinds)
+	if err != nil {
+		return "", err
+	}
+	if len(actions) == 0 {
+		return "", fmt.Errorf("no code actions available")
+	}
+
+	var action protocol.CodeAction
+	switch {
+	case title != "":
+		found := false
+		for _, a := range actions {
+			if a.Title == title {
+				action, found = a, true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("no code action titled %q", title)
+		}
+	case index >= 1 && index <= len(actions):
+		action = actions[index-1]
+	default:
+		return "", fmt.Errorf("invalid code action index: %d. Available range: 1-%d", index, len(actions))
+	}
+
+	return applyCodeAction(ctx, client, action)
+}
+
+// getCodeActions requests the code actions for lines of a file
+func getCodeActions(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine int, kinds []string) ([]protocol.CodeAction, error) {
+	// Actions carry edits computed against the server's copy of the file
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return nil, err
+	}
+
+	if endLine < startLine {
+		endLine = startLine
+	}
+	rng, err := getRange(startLine, endLine, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid position: %v", err)
+	}
+
+	uri := protocol.DocumentUri("file://" + filePath)
+	diagnostics, err := waitForDiagnostics(ctx, client, uri, diagnosticsTimeout)
+	if err != nil {
+		return nil, err
+	}
+	// The diagnostics list is required, even if empty
+	related := []protocol.Diagnostic{}
+	for _, diag := range diagnostics {
+		if utilities.RangesOverlap(diag.Range, rng) {
+			related = append(related, diag)
+		}
+	}
+
+	var only []protocol.CodeActionKind
+	for _, kind := range kinds {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			only = append(only, protocol.CodeActionKind(kind))
+		}
+	}
+
+	triggerKind := protocol.CodeActionInvoked
+	result, err := client.CodeAction(ctx, protocol.CodeActionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Range:        rng,
+		Context: protocol.CodeActionContext{
+			Diagnostics: related,
+			Only:        only,
+			TriggerKind: &triggerKind,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code actions: %v", err)
+	}
+
+	return codeActionsFromResult(result), nil
+}
+
+// codeActionsFromResult converts a code action response to code actions.
+// Bare commands are returned as code actions with only a command.
+func codeActionsFromResult(result []protocol.Or_Result_textDocument_codeAction_Item0_Elem) []protocol.CodeAction {
+	var actions []protocol.CodeAction
+	for _, item := range result {
+		switch v := item.Value.(type) {
+		case protocol.CodeAction:
+			actions = append(actions, v)
+		case protocol.Command:
+			actions = append(actions, protocol.CodeAction{Title: v.Title, Command: &v})
+		}
+	}
+	return actions
+}
+
+// applyCodeAction resolves a code action if needed, applies its edit and
+// executes its command
+func applyCodeAction(ctx context.Context, client *lsp.Client, action protocol.CodeAction) (string, error) {
+	if action.Disabled != nil {
+		return "", fmt.Errorf("code action %q is disabled: %s", action.Title, action.Disabled.Reason)
+	}
+
+	// Servers may leave out the edit until the action is resolved
+	if action.Edit == nil && (action.Data != nil || action.Command == nil) {
+		resolved, err := client.ResolveCodeAction(ctx, action)
+		if err != nil {
+			if action.Command == nil {
+				return "", fmt.Errorf("failed to resolve code action: %v", err)
+			}
+			toolsLogger.Debug("Failed to resolve code action %q, executing its command: %v", action.Title, err)
+		} else {
+			action = resolved
+		}
+	}
+
+	if action.Edit == nil && action.Command == nil {
+		return "", fmt.Errorf("code action %q has no edit or command", action.Title)
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Applied code action: %s\n", action.Title))
+
+	if action.Edit != nil {
+		if err := utilities.ApplyWorkspaceEdit(*action.Edit); err != nil {
+			return "", fmt.Errorf("failed to apply changes: %v", err)
+		}
+		output.WriteString(workspaceEditSummary(*action.Edit))
+	}
+
+	// Commands may change files by sending a workspace/applyEdit request
+	if action.Command != nil {
+		_, err := client.ExecuteCommand(ctx, protocol.ExecuteCommandParams{
+			Command:   action.Command.Command,
+			Arguments: action.Command.Arguments,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to execute code action command: %v", err)
+		}
+		output.WriteString(fmt.Sprintf("Executed command: %s\n", action.Command.Command))
+	}
+
+	return output.String(), nil
+}
+
+// workspaceEditSummary lists the files changed by a workspace edit and the
+// number of edits in each
+func workspaceEditSummary(edit protocol.WorkspaceEdit) string {
+	counts := make(map[string]int)
+	var operations []string
+	for uri, edits := range edit.Changes {
+		counts[uriPath(uri)] += len(edits)
+	}
+	for _, change := range edit.DocumentChanges {
+		switch {
+		case change.TextDocumentEdit != nil:
+			counts[uriPath(change.TextDocumentEdit.TextDocument.URI)] += len(change.TextDocumentEdit.Edits)
+		case change.CreateFile != nil:
+			operations = append(operations, "Created "+uriPath(change.CreateFile.URI))
+		case change.RenameFile != nil:
+			operations = append(operations, fmt.Sprintf("Renamed %s to %s", uriPath(change.RenameFile.OldURI), uriPath(change.RenameFile.NewURI)))
+		case change.DeleteFile != nil:
+			operations = append(operations, "Deleted "+uriPath(change.DeleteFile.URI))
+		}
+	}
+
+	paths := make([]string, 0, len(counts))
+	for path := range counts {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var output strings.Builder
+	for _, path := range paths {
+		output.WriteString(fmt.Sprintf("%s: %d edits\n", path, counts[path]))
+	}
+	for _, op := range operations {
+		output.WriteString(op + "\n")
+	}
+	return output.String()
+}
+
+// uriPath returns the path of a file URI without failing on malformed URIs
+func uriPath(uri protocol.DocumentUri) string {
+	return strings.TrimPrefix(string(uri), "file://")
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceEditSummary(t *testing.T) {
+	edit := func(line uint32) protocol.TextEdit {
+		return protocol.TextEdit{Range: lineRange(line, line), NewText: "x"}
+	}
+	textDocumentEdit := func(uri protocol.DocumentUri, count int) protocol.DocumentChange {
+		edits := make([]protocol.Or_TextDocumentEdit_edits_Elem, count)
+		for i := range edits {
+			edits[i] = protocol.Or_TextDocumentEdit_edits_Elem{Value: edit(uint32(i))}
+		}
+		return protocol.DocumentChange{TextDocumentEdit: &protocol.TextDocumentEdit{
+			TextDocument: protocol.OptionalVersionedTextDocumentIdentifier{
+				TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: uri},
+			},
+			Edits: edits,
+		}}
+	}
+
+	summary := workspaceEditSummary(protocol.WorkspaceEdit{
+		Changes: map[protocol.DocumentUri][]protocol.TextEdit{
+			"file:///src/main.go": {edit(1), edit(2)},
+		},
+		DocumentChanges: []protocol.DocumentChange{
+			{CreateFile: &protocol.CreateFile{URI: "file:///src/helpers.go"}},
+			textDocumentEdit("file:///src/helpers.go", 1),
+			textDocumentEdit("file:///src/main.go", 1),
+			{RenameFile: &protocol.RenameFile{OldURI: "file:///src/old.go", NewURI: "file:///src/new.go"}},
+		},
+	})
+
+	assert.Equal(t, "/src/helpers.go: 1 edits\n"+
+		"/src/main.go: 3 edits\n"+
+		"Created /src/helpers.go\n"+
+		"Renamed /src/old.go to /src/new.go\n", summary)
+}
@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// DefaultCompletionLimit is the number of completions listed when no limit is
+// given
+const DefaultCompletionLimit = 30
+
+// maxResolvedCompletions is the number of listed completions whose missing
+// documentation is requested from the server
+const maxResolvedCompletions = 10
+
+// maxCompletionDocLength is the length a completion's documentation is cut to
+const maxCompletionDocLength = 200
+
+// GetCompletions lists the completions the server offers at a position. If
+// filter is set only completions containing it are listed, those starting
+// with it first.
+func GetCompletions(ctx context.Context, client *lsp.Client, filePath string, line, column int, filter string, limit int) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	if limit <= 0 {
+		limit = DefaultCompletionLimit
+	}
+
+	result, err := client.Completion(ctx, protocol.CompletionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{
+				URI: protocol.DocumentUri("file://" + filePath),
+			},
+			Position: protocol.Position{
+				Line:      uint32(line - 1),
+				Character: uint32(column - 1),
+			},
+		},
+		Context: protocol.CompletionContext{
+			TriggerKind: protocol.Invoked,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get completions: %v", err)
+	}
+
+	items, incomplete, err := result.Items()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse completions: %v", err)
+	}
+
+	location := fmt.Sprintf("%s:%d:%d", filepath.Base(filePath), line, column)
+	ranked := rankCompletions(items, filter)
+	if len(ranked) == 0 {
+		if filter != "" {
+			return fmt.Sprintf("No completions matching %q at %s", filter, location), nil
+		}
+		return fmt.Sprintf("No completions at %s", location), nil
+	}
+
+	total := len(ranked)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	if client.SupportsCompletionResolve() {
+		for i := range ranked[:min(len(ranked), maxResolvedCompletions)] {
+			if ranked[i].Documentation != nil && ranked[i].Detail != "" {
+				continue
+			}
+			resolved, err := client.ResolveCompletionItem(ctx, ranked[i])
+			if err != nil {
+				toolsLogger.Debug("failed to resolve completion %s: %v", ranked[i].Label, err)
+				continue
+			}
+			ranked[i] = resolved
+		}
+	}
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "Completions at %s", location)
+	if total > len(ranked) {
+		fmt.Fprintf(&output, " (showing %d of %d)", len(ranked), total)
+	}
+	output.WriteString(":\n")
+	for _, item := range ranked {
+		output.WriteString(formatCompletion(item))
+	}
+	if incomplete {
+		output.WriteString("\nThe server returned a partial list. Complete after more of the name to see other candidates.\n")
+	}
+	return output.String(), nil
+}
+
+// rankCompletions drops completions that don't contain filter and orders the
+// rest: prefix matches first, then deprecated ones last, then the server's
+// preselected and sort order
+func rankCompletions(items []protocol.CompletionItem, filter string) []protocol.CompletionItem {
+	type ranked struct {
+		item  protocol.CompletionItem
+		score int
+	}
+
+	lowerFilter := strings.ToLower(filter)
+	var matches []ranked
+	for _, item := range items {
+		text := item.FilterText
+		if text == "" {
+			text = item.Label
+		}
+
+		score := 0
+		switch {
+		case strings.HasPrefix(text, filter):
+			score = 0
+		case strings.HasPrefix(strings.ToLower(text), lowerFilter):
+			score = 1
+		case strings.Contains(strings.ToLower(text), lowerFilter):
+			score = 2
+		default:
+			continue
+		}
+		matches = append(matches, ranked{item, score})
+	}
+
+	sortText := func(item protocol.CompletionItem) string {
+		if item.SortText != "" {
+			return item.SortText
+		}
+		return item.Label
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.score != b.score {
+			return a.score < b.score
+		}
+		if isDeprecated(a.item) != isDeprecated(b.item) {
+			return !isDeprecated(a.item)
+		}
+		if a.item.Preselect != b.item.Preselect {
+			return a.item.Preselect
+		}
+		return sortText(a.item) < sortText(b.item)
+	})
+
+	result := make([]protocol.CompletionItem, len(matches))
+	for i, match := range matches {
+		result[i] = match.item
+	}
+	return result
+}
+
+// formatCompletion describes a completion on one line, followed by the start
+// of its documentation
+func formatCompletion(item protocol.CompletionItem) string {
+	var line strings.Builder
+	if kind, ok := protocol.CompletionKindMap[item.Kind]; ok {
+		line.WriteString(kind + " ")
+	}
+	line.WriteString(item.Label)
+	if item.LabelDetails != nil {
+		line.WriteString(item.LabelDetails.Detail)
+	}
+	if item.Detail != "" && item.Detail != item.Label {
+		line.WriteString(" - " + item.Detail)
+	} else if item.LabelDetails != nil && item.LabelDetails.Description != "" {
+		line.WriteString(" - " + item.LabelDetails.Description)
+	}
+	if isDeprecated(item) {
+		line.WriteString(" (deprecated)")
+	}
+	line.WriteString("\n")
+
+	if doc := firstParagraph(item.Documentation.Text()); doc != "" {
+		if len(doc) > maxCompletionDocLength {
+			doc = doc[:maxCompletionDocLength] + "..."
+		}
+		line.WriteString("    " + doc + "\n")
+	}
+	return line.String()
+}
+
+// isDeprecated reports whether a completion is marked deprecated by either
+// the deprecated property or tag
+func isDeprecated(item protocol.CompletionItem) bool {
+	return item.Deprecated || slices.Contains(item.Tags, protocol.ComplDeprecated)
+}
+
+// firstParagraph returns the first paragraph of documentation on one line
+func firstParagraph(doc string) string {
+	doc = strings.TrimSpace(doc)
+	if i := strings.Index(doc, "\n\n"); i >= 0 {
+		doc = doc[:i]
+	}
+	return strings.Join(strings.Fields(doc), " ")
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankCompletions(t *testing.T) {
+	items := []protocol.CompletionItem{
+		{Label: "Sprintf", SortText: "00002"},
+		{Label: "Println", SortText: "00001"},
+		{Label: "Print", SortText: "00003", Deprecated: true},
+		{Label: "Printf", SortText: "00004"},
+		{Label: "println", SortText: "00000"},
+		{Label: "Errorf", SortText: "00005"},
+	}
+
+	labels := func(items []protocol.CompletionItem) []string {
+		var result []string
+		for _, item := range items {
+			result = append(result, item.Label)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Println", "Printf", "Print", "println", "Sprintf"}, labels(rankCompletions(items, "Print")))
+	assert.Equal(t, []string{"println", "Println", "Sprintf", "Printf", "Errorf", "Print"}, labels(rankCompletions(items, "")))
+
+	preselected := []protocol.CompletionItem{{Label: "a"}, {Label: "b", Preselect: true}}
+	assert.Equal(t, []string{"b", "a"}, labels(rankCompletions(preselected, "")))
+}
+
+func TestFormatCompletion(t *testing.T) {
+	tests := []struct {
+		name string
+		item protocol.CompletionItem
+		want string
+	}{
+		{
+			name: "Detail and documentation",
+			item: protocol.CompletionItem{
+				Label:  "Println",
+				Kind:   protocol.FunctionCompletion,
+				Detail: "func(a ...any) (n int, err error)",
+				Documentation: &protocol.Or_CompletionItem_documentation{Value: protocol.MarkupContent{
+					Kind:  protocol.Markdown,
+					Value: "Println formats using the default formats\nfor its operands.\n\nSpaces are always added.",
+				}},
+			},
+			want: "Function Println - func(a ...any) (n int, err error)\n" +
+				"    Println formats using the default formats for its operands.\n",
+		},
+		{
+			name: "Label details",
+			item: protocol.CompletionItem{
+				Label:        "useState",
+				Kind:         protocol.FunctionCompletion,
+				LabelDetails: &protocol.CompletionItemLabelDetails{Detail: "(initial)", Description: "react"},
+			},
+			want: "Function useState(initial) - react\n",
+		},
+		{
+			name: "Deprecated tag",
+			item: protocol.CompletionItem{
+				Label: "Title",
+				Kind:  protocol.MethodCompletion,
+				Tags:  []protocol.CompletionItemTag{protocol.ComplDeprecated},
+				Documentation: &protocol.Or_CompletionItem_documentation{
+					Value: "Title returns a copy of the string.",
+				},
+			},
+			want: "Method Title (deprecated)\n    Title returns a copy of the string.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatCompletion(tt.item))
+		})
+	}
+}
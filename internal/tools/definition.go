@@ -9,19 +9,39 @@ import (
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
-func ReadDefinition(ctx context.Context, client *lsp.Client, symbolName string) (string, error) {
+// ReadDefinitionAcrossServers looks up a symbol with every language server
+// and merges the definitions they find
+func ReadDefinitionAcrossServers(ctx context.Context, clients []*lsp.Client, symbolName string) (string, error) {
+	definitions, err := fromEachClient(clients, "Definition lookup", func(client *lsp.Client) ([]string, error) {
+		return findDefinitions(ctx, client, symbolName)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(definitions) == 0 {
+		return fmt.Sprintf("%s not found", symbolName) + indexingNotice(clients...), nil
+	}
+
+	return strings.Join(definitions, ""), nil
+}
+
+// findDefinitions returns the formatted definitions of a symbol found by one
+// language server
+func findDefinitions(ctx context.Context, client *lsp.Client, symbolName string) ([]string, error) {
 	symbolResult, err := client.Symbol(ctx, protocol.WorkspaceSymbolParams{
 		Query: symbolName,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch symbol: %v", err)
+		return nil, fmt.Errorf("failed to fetch symbol: %v", err)
 	}
 
 	results, err := symbolResult.Results()
 	if err != nil {
-		return "", fmt.Errorf("failed to parse results: %v", err)
+		return nil, fmt.Errorf("failed to parse results: %v", err)
 	}
 
+	profile := client.Profile()
 	var definitions []string
 	for _, symbol := range results {
 		kind := ""
@@ -38,16 +58,18 @@ func ReadDefinition(ctx context.Context, client *lsp.Client, symbolName string)
 			}
 
 			// Handle different matching strategies based on the search term
-			if strings.Contains(symbolName, ".") {
-				// For qualified names like "Type.Method", require exact match
-				if symbol.GetName() != symbolName {
+			if container, member, ok := profile.SplitSymbolName(symbolName); ok {
+				// For qualified names like "Type.Method", require exact match.
+				// Servers that don't qualify names report the type as the container.
+				if symbol.GetName() != symbolName &&
+					(symbol.GetName() != member || !strings.HasSuffix(v.ContainerName, container)) {
 					continue
 				}
 			} else {
 				// For unqualified names like "Method"
 				if v.Kind == protocol.Method {
-					// For methods, only match if the method name matches exactly Type.symbolName or Type::symbolName or symbolName
-					if !strings.HasSuffix(symbol.GetName(), "::"+symbolName) && !strings.HasSuffix(symbol.GetName(), "."+symbolName) && symbol.GetName() != symbolName {
+					// For methods, only match if the method name matches exactly Type.symbolName or symbolName
+					if !hasQualifiedSuffix(profile, symbol.GetName(), symbolName) && symbol.GetName() != symbolName {
 						continue
 					}
 				} else if symbol.GetName() != symbolName {
@@ -64,41 +86,44 @@ func ReadDefinition(ctx context.Context, client *lsp.Client, symbolName string)
 		toolsLogger.Debug("Found symbol: %s", symbol.GetName())
 		loc := symbol.GetLocation()
 
-		err := client.OpenFile(ctx, loc.URI.Path())
-		if err != nil {
-			toolsLogger.Error("Error opening file: %v", err)
-			continue
-		}
-
-		banner := "---\n\n"
-		definition, loc, err := GetFullDefinition(ctx, client, loc)
-		locationInfo := fmt.Sprintf(
-			"Symbol: %s\n"+
-				"File: %s\n"+
-				kind+
-				container+
-				"Range: L%d:C%d - L%d:C%d\n\n",
-			symbol.GetName(),
-			strings.TrimPrefix(string(loc.URI), "file://"),
-			loc.Range.Start.Line+1,
-			loc.Range.Start.Character+1,
-			loc.Range.End.Line+1,
-			loc.Range.End.Character+1,
-		)
-
+		header := fmt.Sprintf("Symbol: %s\n", symbol.GetName())
+		text, err := formatFullDefinition(ctx, client, loc, header, kind+container)
 		if err != nil {
 			toolsLogger.Error("Error getting definition: %v", err)
 			continue
 		}
+		definitions = append(definitions, text)
+	}
 
-		definition = addLineNumbers(definition, int(loc.Range.Start.Line)+1)
+	return definitions, nil
+}
 
-		definitions = append(definitions, banner+locationInfo+definition+"\n")
+// formatFullDefinition returns the full source of the symbol at a location,
+// with line numbers, after a header giving its file and range. header is
+// written before the file and details between the file and the range.
+func formatFullDefinition(ctx context.Context, client *lsp.Client, loc protocol.Location, header, details string) (string, error) {
+	err := client.OpenFile(ctx, loc.URI.Path())
+	if err != nil {
+		return "", fmt.Errorf("could not open file: %v", err)
 	}
 
-	if len(definitions) == 0 {
-		return fmt.Sprintf("%s not found", symbolName), nil
+	definition, loc, err := GetFullDefinition(ctx, client, loc)
+	if err != nil {
+		return "", err
 	}
 
-	return strings.Join(definitions, ""), nil
+	locationInfo := fmt.Sprintf(
+		"%sFile: %s\n"+
+			"%sRange: L%d:C%d - L%d:C%d\n\n",
+		header,
+		strings.TrimPrefix(string(loc.URI), "file://"),
+		details,
+		loc.Range.Start.Line+1,
+		loc.Range.Start.Character+1,
+		loc.Range.End.Line+1,
+		loc.Range.End.Character+1,
+	)
+
+	definition = addLineNumbers(definition, int(loc.Range.Start.Line)+1)
+	return "---\n\n" + locationInfo + definition + "\n", nil
 }
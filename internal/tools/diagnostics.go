@@ -26,25 +26,14 @@ func GetDiagnosticsForFile(ctx context.Context, client *lsp.Client, filePath str
 		return "", fmt.Errorf("could not open file: %v", err)
 	}
 
-	// Wait for diagnostics
-	// TODO: wait for notification
-	time.Sleep(time.Second * 3)
-
 	// Convert the file path to URI format
 	uri := protocol.DocumentUri("file://" + filePath)
 
-	// Request fresh diagnostics
-	diagParams := protocol.DocumentDiagnosticParams{
-		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
-	}
-	_, err = client.Diagnostic(ctx, diagParams)
+	diagnostics, err := waitForDiagnostics(ctx, client, uri, diagnosticsTimeout)
 	if err != nil {
-		toolsLogger.Error("Failed to get diagnostics: %v", err)
+		return "", err
 	}
 
-	// Get diagnostics from the cache
-	diagnostics := client.GetFileDiagnostics(uri)
-
 	if len(diagnostics) == 0 {
 		return "No diagnostics found for " + filePath, nil
 	}
@@ -135,6 +124,50 @@ func GetDiagnosticsForFile(ctx context.Context, client *lsp.Client, filePath str
 	return result, nil
 }
 
+// diagnosticsTimeout is how long to wait for the server to report diagnostics
+// for a document before using whatever is cached
+const diagnosticsTimeout = 10 * time.Second
+
+// pullDiagnosticsDelay is how long to wait for pushed diagnostics before
+// asking servers that support it for them directly
+const pullDiagnosticsDelay = time.Second
+
+// waitForDiagnostics returns diagnostics for the version of an open document
+// that was last sent to the server. It waits for the server to publish them,
+// pulls them if the server supports textDocument/diagnostic and they are slow
+// to arrive, and falls back to the cache once timeout elapses.
+func waitForDiagnostics(ctx context.Context, client *lsp.Client, uri protocol.DocumentUri, timeout time.Duration) ([]protocol.Diagnostic, error) {
+	version, _ := client.FileVersion(uri)
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if client.SupportsPullDiagnostics() {
+		pushCtx, pushCancel := context.WithTimeout(waitCtx, pullDiagnosticsDelay)
+		diagnostics, err := client.WaitForDiagnostics(pushCtx, uri, version)
+		pushCancel()
+		if err == nil {
+			return diagnostics, nil
+		}
+
+		diagnostics, err = client.PullDiagnostics(waitCtx, uri)
+		if err == nil {
+			return diagnostics, nil
+		}
+		toolsLogger.Warn("Failed to pull diagnostics for %s: %v", uri, err)
+	}
+
+	diagnostics, err := client.WaitForDiagnostics(waitCtx, uri, version)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		toolsLogger.Warn("No diagnostics received for %s version %d within %s, using cached diagnostics", uri, version, timeout)
+		return client.GetFileDiagnostics(uri), nil
+	}
+	return diagnostics, nil
+}
+
 func getSeverityString(severity protocol.DiagnosticSeverity) string {
 	switch severity {
 	case protocol.SeverityError:
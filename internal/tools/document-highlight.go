@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// highlightKindNames names document highlight kinds
+var highlightKindNames = map[protocol.DocumentHighlightKind]string{
+	protocol.Text:  "text",
+	protocol.Read:  "read",
+	protocol.Write: "write",
+}
+
+// GetDocumentHighlights lists the occurrences in a file of the symbol at a
+// position, each marked as a read, a write or a textual match
+func GetDocumentHighlights(ctx context.Context, client *lsp.Client, filePath string, line, column int) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	highlights, err := client.DocumentHighlight(ctx, protocol.DocumentHighlightParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{
+				URI: protocol.DocumentUri("file://" + filePath),
+			},
+			Position: protocol.Position{
+				Line:      uint32(line - 1),
+				Character: uint32(column - 1),
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get document highlights: %v", err)
+	}
+
+	if len(highlights) == 0 {
+		return fmt.Sprintf("No occurrences found for the symbol at %s:%d:%d", filepath.Base(filePath), line, column), nil
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+
+	return formatHighlights(filepath.Base(filePath), lines, highlights), nil
+}
+
+// formatHighlights lists highlights in file order with their kind and line
+func formatHighlights(fileName string, lines []string, highlights []protocol.DocumentHighlight) string {
+	sort.SliceStable(highlights, func(i, j int) bool {
+		a, b := highlights[i].Range.Start, highlights[j].Range.Start
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Character < b.Character
+	})
+
+	counts := make(map[string]int)
+	var entries []string
+	for _, highlight := range highlights {
+		// Highlights without a kind are textual matches
+		kind, ok := highlightKindNames[highlight.Kind]
+		if !ok {
+			kind = highlightKindNames[protocol.Text]
+		}
+		counts[kind]++
+
+		var code string
+		if lineIndex := int(highlight.Range.Start.Line); lineIndex < len(lines) {
+			code = strings.TrimSpace(lines[lineIndex])
+		}
+		entries = append(entries, fmt.Sprintf("L%d:C%d %-5s | %s",
+			highlight.Range.Start.Line+1, highlight.Range.Start.Character+1, kind, code))
+	}
+
+	name := singleLineText(lines, highlights[0].Range)
+
+	var summary []string
+	for _, kind := range []string{"write", "read", "text"} {
+		if counts[kind] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+
+	var result strings.Builder
+	if name != "" {
+		fmt.Fprintf(&result, "%d occurrences of %s in %s (%s):\n", len(highlights), name, fileName, strings.Join(summary, ", "))
+	} else {
+		fmt.Fprintf(&result, "%d occurrences in %s (%s):\n", len(highlights), fileName, strings.Join(summary, ", "))
+	}
+	for _, entry := range entries {
+		result.WriteString(entry + "\n")
+	}
+	return result.String()
+}
+
+// singleLineText returns the text of a range within one line, or "" if the
+// range spans lines or is outside them
+func singleLineText(lines []string, rng protocol.Range) string {
+	if rng.Start.Line != rng.End.Line || int(rng.Start.Line) >= len(lines) {
+		return ""
+	}
+	line := lines[rng.Start.Line]
+	start := byteOffset(line, rng.Start.Character)
+	end := max(byteOffset(line, rng.End.Character), start)
+	return line[start:end]
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatHighlights(t *testing.T) {
+	at := func(line, start, end uint32, kind protocol.DocumentHighlightKind) protocol.DocumentHighlight {
+		return protocol.DocumentHighlight{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: line, Character: start},
+				End:   protocol.Position{Line: line, Character: end},
+			},
+			Kind: kind,
+		}
+	}
+
+	lines := []string{
+		"func total(items []int) int {",
+		"\tsum := 0",
+		"\tfor _, item := range items {",
+		"\t\tsum += item",
+		"\t}",
+		"\t// sum is the total",
+		"\treturn sum",
+		"}",
+	}
+	highlights := []protocol.DocumentHighlight{
+		at(6, 8, 11, protocol.Read),
+		at(1, 1, 4, protocol.Write),
+		at(3, 2, 5, protocol.Write),
+		at(5, 4, 7, 0),
+	}
+
+	assert.Equal(t, "4 occurrences of sum in main.go (2 write, 1 read, 1 text):\n"+
+		"L2:C2 write | sum := 0\n"+
+		"L4:C3 write | sum += item\n"+
+		"L6:C5 text  | // sum is the total\n"+
+		"L7:C9 read  | return sum\n", formatHighlights("main.go", lines, highlights))
+}
+
+func TestSingleLineText(t *testing.T) {
+	lines := []string{"\tgrüße := \"日本\" + grüße"}
+	rng := func(startLine, start, endLine, end uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: startLine, Character: start},
+			End:   protocol.Position{Line: endLine, Character: end},
+		}
+	}
+
+	// Characters count UTF-16 units, not bytes
+	assert.Equal(t, "grüße", singleLineText(lines, rng(0, 1, 0, 6)))
+	assert.Equal(t, "grüße", singleLineText(lines, rng(0, 17, 0, 22)))
+	assert.Equal(t, "", singleLineText(lines, rng(0, 1, 1, 6)))
+	assert.Equal(t, "", singleLineText(lines, rng(2, 0, 2, 1)))
+}
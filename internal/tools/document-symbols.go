@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// outlineNode is a symbol in a document outline
+type outlineNode struct {
+	name     string
+	detail   string
+	kind     protocol.SymbolKind
+	rng      protocol.Range
+	children []*outlineNode
+}
+
+// GetDocumentSymbols returns an outline of the symbols in a file. maxDepth
+// limits how deeply nested symbols are shown, zero shows everything. kinds
+// limits the outline to symbols of those kinds, e.g. "function" or "class",
+// along with the symbols containing them.
+func GetDocumentSymbols(ctx context.Context, client *lsp.Client, filePath string, maxDepth int, kinds []string) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	kindFilter, err := parseSymbolKinds(kinds)
+	if err != nil {
+		return "", err
+	}
+
+	symResult, err := client.DocumentSymbol(ctx, protocol.DocumentSymbolParams{
+		TextDocument: protocol.TextDocumentIdentifier{
+			URI: protocol.DocumentUri("file://" + filePath),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get document symbols: %v", err)
+	}
+
+	symbols, err := symResult.Results()
+	if err != nil {
+		return "", fmt.Errorf("failed to process document symbols: %v", err)
+	}
+
+	outline := buildOutline(symbols)
+	text := formatOutline(outline, maxDepth, kindFilter)
+	if text == "" {
+		return fmt.Sprintf("No symbols found in %s", filepath.Base(filePath)) + indexingNotice(client), nil
+	}
+
+	return fmt.Sprintf("Symbols in %s:\n%s", filepath.Base(filePath), text), nil
+}
+
+// buildOutline converts document symbols to an outline. Servers that return
+// flat SymbolInformation lists are nested by range.
+func buildOutline(symbols []protocol.DocumentSymbolResult) []*outlineNode {
+	var nodes []*outlineNode
+	var flat []*outlineNode
+	for _, sym := range symbols {
+		switch v := sym.(type) {
+		case *protocol.DocumentSymbol:
+			nodes = append(nodes, documentSymbolNode(v))
+		case *protocol.SymbolInformation:
+			flat = append(flat, &outlineNode{
+				name: v.Name,
+				kind: v.Kind,
+				rng:  v.Location.Range,
+			})
+		}
+	}
+
+	if len(flat) > 0 {
+		nodes = append(nodes, nestByRange(flat)...)
+	}
+
+	sortOutline(nodes)
+	return nodes
+}
+
+func documentSymbolNode(sym *protocol.DocumentSymbol) *outlineNode {
+	node := &outlineNode{
+		name:   sym.Name,
+		detail: sym.Detail,
+		kind:   sym.Kind,
+		rng:    sym.Range,
+	}
+	for i := range sym.Children {
+		node.children = append(node.children, documentSymbolNode(&sym.Children[i]))
+	}
+	return node
+}
+
+// nestByRange makes each symbol a child of the innermost symbol whose range
+// contains it
+func nestByRange(flat []*outlineNode) []*outlineNode {
+	// Outer symbols first when several start at the same position
+	sort.SliceStable(flat, func(i, j int) bool {
+		a, b := flat[i].rng, flat[j].rng
+		if a.Start != b.Start {
+			return positionBefore(a.Start, b.Start)
+		}
+		return positionBefore(b.End, a.End)
+	})
+
+	var roots []*outlineNode
+	var stack []*outlineNode
+	for _, node := range flat {
+		for len(stack) > 0 && !rangeContains(stack[len(stack)-1].rng, node.rng) {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, node)
+		}
+		stack = append(stack, node)
+	}
+	return roots
+}
+
+func sortOutline(nodes []*outlineNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return positionBefore(nodes[i].rng.Start, nodes[j].rng.Start)
+	})
+	for _, node := range nodes {
+		sortOutline(node.children)
+	}
+}
+
+// formatOutline writes one line per symbol, indented by depth
+func formatOutline(nodes []*outlineNode, maxDepth int, kinds map[protocol.SymbolKind]bool) string {
+	var output strings.Builder
+
+	var write func(nodes []*outlineNode, depth int)
+	write = func(nodes []*outlineNode, depth int) {
+		if maxDepth > 0 && depth >= maxDepth {
+			return
+		}
+		for _, node := range nodes {
+			if len(kinds) > 0 && !outlineHasKind(node, kinds, maxDepth-depth) {
+				continue
+			}
+
+			output.WriteString(strings.Repeat("  ", depth))
+			output.WriteString(fmt.Sprintf("%s %s", protocol.TableKindMap[node.kind], node.name))
+			if node.detail != "" {
+				output.WriteString(" " + strings.ReplaceAll(node.detail, "\n", " "))
+			}
+			start, end := node.rng.Start.Line+1, node.rng.End.Line+1
+			if start == end {
+				output.WriteString(fmt.Sprintf(" (L%d)\n", start))
+			} else {
+				output.WriteString(fmt.Sprintf(" (L%d-L%d)\n", start, end))
+			}
+
+			write(node.children, depth+1)
+		}
+	}
+	write(nodes, 0)
+
+	return output.String()
+}
+
+// outlineHasKind reports whether a symbol, or one of its descendants within
+// levels, is of one of the kinds. levels <= 0 means any depth.
+func outlineHasKind(node *outlineNode, kinds map[protocol.SymbolKind]bool, levels int) bool {
+	if kinds[node.kind] {
+		return true
+	}
+	if levels == 1 {
+		return false
+	}
+	for _, child := range node.children {
+		if outlineHasKind(child, kinds, levels-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSymbolKinds converts kind names such as "function" or "class" to
+// symbol kinds
+func parseSymbolKinds(names []string) (map[protocol.SymbolKind]bool, error) {
+	kinds := make(map[protocol.SymbolKind]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for kind, kindName := range protocol.TableKindMap {
+			if strings.EqualFold(kindName, name) {
+				kinds[kind] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown symbol kind: %s", name)
+		}
+	}
+	return kinds, nil
+}
+
+func positionBefore(a, b protocol.Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Character < b.Character
+}
+
+// rangeContains reports whether inner lies within outer
+func rangeContains(outer, inner protocol.Range) bool {
+	return !positionBefore(inner.Start, outer.Start) && !positionBefore(outer.End, inner.End)
+}
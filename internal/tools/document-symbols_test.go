@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lineRange(start, end uint32) protocol.Range {
+	return protocol.Range{
+		Start: protocol.Position{Line: start},
+		End:   protocol.Position{Line: end, Character: 1},
+	}
+}
+
+func TestDocumentSymbolOutline(t *testing.T) {
+	symbols := []protocol.DocumentSymbol{
+		{
+			Name: "Server", Kind: protocol.Struct, Detail: "struct{...}", Range: lineRange(9, 13),
+			Children: []protocol.DocumentSymbol{
+				{Name: "name", Kind: protocol.Field, Detail: "string", Range: lineRange(10, 10)},
+				{Name: "port", Kind: protocol.Field, Detail: "int", Range: lineRange(11, 11)},
+			},
+		},
+		{Name: "main", Kind: protocol.Function, Detail: "func()", Range: lineRange(2, 6)},
+		{Name: "(*Server).Start", Kind: protocol.Method, Detail: "func() error", Range: lineRange(15, 20)},
+	}
+	results := make([]protocol.DocumentSymbolResult, len(symbols))
+	for i := range symbols {
+		results[i] = &symbols[i]
+	}
+	outline := buildOutline(results)
+
+	t.Run("Full outline in source order", func(t *testing.T) {
+		assert.Equal(t, "Function main func() (L3-L7)\n"+
+			"Struct Server struct{...} (L10-L14)\n"+
+			"  Field name string (L11)\n"+
+			"  Field port int (L12)\n"+
+			"Method (*Server).Start func() error (L16-L21)\n",
+			formatOutline(outline, 0, nil))
+	})
+
+	t.Run("Depth limit", func(t *testing.T) {
+		assert.Equal(t, "Function main func() (L3-L7)\n"+
+			"Struct Server struct{...} (L10-L14)\n"+
+			"Method (*Server).Start func() error (L16-L21)\n",
+			formatOutline(outline, 1, nil))
+	})
+
+	t.Run("Kind filter keeps containers", func(t *testing.T) {
+		kinds, err := parseSymbolKinds([]string{"field"})
+		require.NoError(t, err)
+		assert.Equal(t, "Struct Server struct{...} (L10-L14)\n"+
+			"  Field name string (L11)\n"+
+			"  Field port int (L12)\n",
+			formatOutline(outline, 0, kinds))
+
+		// Fields are too deep to be shown
+		assert.Equal(t, "", formatOutline(outline, 1, kinds))
+	})
+}
+
+func TestSymbolInformationOutline(t *testing.T) {
+	location := func(start, end uint32) protocol.Location {
+		return protocol.Location{URI: "file:///ws/app.py", Range: lineRange(start, end)}
+	}
+	symbols := []protocol.SymbolInformation{
+		{Name: "run", Kind: protocol.Method, Location: location(5, 8)},
+		{Name: "App", Kind: protocol.Class, Location: location(0, 8)},
+		{Name: "__init__", Kind: protocol.Method, Location: location(1, 3)},
+		{Name: "main", Kind: protocol.Function, Location: location(11, 12)},
+	}
+	results := make([]protocol.DocumentSymbolResult, len(symbols))
+	for i := range symbols {
+		results[i] = &symbols[i]
+	}
+
+	assert.Equal(t, "Class App (L1-L9)\n"+
+		"  Method __init__ (L2-L4)\n"+
+		"  Method run (L6-L9)\n"+
+		"Function main (L12-L13)\n",
+		formatOutline(buildOutline(results), 0, nil))
+}
+
+func TestParseSymbolKinds(t *testing.T) {
+	kinds, err := parseSymbolKinds([]string{"Class", " function ", ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[protocol.SymbolKind]bool{protocol.Class: true, protocol.Function: true}, kinds)
+
+	_, err = parseSymbolKinds([]string{"widget"})
+	assert.Error(t, err)
+}
@@ -16,14 +16,17 @@ func ExecuteCodeLens(ctx context.Context, client *lsp.Client, filePath string, i
 	if err != nil {
 		return "", fmt.Errorf("could not open file: %v", err)
 	}
-	// TODO: find a more appropriate way to wait
-	time.Sleep(time.Second)
 
 	// Get code lenses
 	docIdentifier := protocol.TextDocumentIdentifier{
 		URI: protocol.DocumentUri("file://" + filePath),
 	}
 
+	// Diagnostics arriving means the server has processed the file
+	if _, err := waitForDiagnostics(ctx, client, docIdentifier.URI, time.Second); err != nil {
+		return "", err
+	}
+
 	params := protocol.CodeLensParams{
 		TextDocument: docIdentifier,
 	}
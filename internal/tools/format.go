@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// defaultTabSize is used when a file's indentation doesn't show its tab size
+const defaultTabSize = 4
+
+// FormatOptions overrides the formatting options detected from a file. Nil
+// fields keep the detected value.
+type FormatOptions struct {
+	TabSize                *int
+	InsertSpaces           *bool
+	TrimTrailingWhitespace *bool
+	InsertFinalNewline     *bool
+	TrimFinalNewlines      *bool
+}
+
+// FormatFile formats a file with the language server's formatter and writes
+// the result. If startLine is positive only the lines from startLine to
+// endLine are formatted.
+func FormatFile(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine int, options FormatOptions) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	uri := protocol.DocumentUri("file://" + filePath)
+	formattingOptions := options.apply(detectFormattingOptions(string(content)))
+
+	var edits []protocol.TextEdit
+	if startLine > 0 {
+		if endLine < startLine {
+			endLine = startLine
+		}
+		rng, err := getRange(startLine, endLine, filePath)
+		if err != nil {
+			return "", fmt.Errorf("invalid position: %v", err)
+		}
+		edits, err = client.RangeFormatting(ctx, protocol.DocumentRangeFormattingParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Range:        rng,
+			Options:      formattingOptions,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to format range: %v", err)
+		}
+	} else {
+		edits, err = client.Formatting(ctx, protocol.DocumentFormattingParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Options:      formattingOptions,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to format file: %v", err)
+		}
+	}
+
+	if len(edits) == 0 {
+		return fmt.Sprintf("%s is already formatted", filepath.Base(filePath)), nil
+	}
+
+	if err := utilities.ApplyTextEdits(uri, edits); err != nil {
+		return "", fmt.Errorf("failed to apply formatting: %v", err)
+	}
+
+	return fmt.Sprintf("Formatted %s: %d edits on lines %s", filepath.Base(filePath), len(edits), editedLines(edits)), nil
+}
+
+// apply overrides the detected options with the ones that are set
+func (o FormatOptions) apply(options protocol.FormattingOptions) protocol.FormattingOptions {
+	if o.TabSize != nil && *o.TabSize > 0 {
+		options.TabSize = uint32(*o.TabSize)
+	}
+	if o.InsertSpaces != nil {
+		options.InsertSpaces = *o.InsertSpaces
+	}
+	if o.TrimTrailingWhitespace != nil {
+		options.TrimTrailingWhitespace = *o.TrimTrailingWhitespace
+	}
+	if o.InsertFinalNewline != nil {
+		options.InsertFinalNewline = *o.InsertFinalNewline
+	}
+	if o.TrimFinalNewlines != nil {
+		options.TrimFinalNewlines = *o.TrimFinalNewlines
+	}
+	return options
+}
+
+// detectFormattingOptions guesses a file's indentation. Files indented with
+// tabs keep tabs; otherwise the smallest space indentation is the tab size.
+func detectFormattingOptions(content string) protocol.FormattingOptions {
+	options := protocol.FormattingOptions{
+		TabSize:      defaultTabSize,
+		InsertSpaces: true,
+	}
+
+	tabs, spaces := 0, 0
+	smallest := 0
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			tabs++
+		case strings.HasPrefix(line, " "):
+			spaces++
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			// Ignore single spaces, which are usually alignment such as in
+			// block comments
+			if indent > 1 && (smallest == 0 || indent < smallest) {
+				smallest = indent
+			}
+		}
+	}
+
+	if tabs > spaces {
+		options.InsertSpaces = false
+	} else if smallest > 0 {
+		options.TabSize = uint32(smallest)
+	}
+	return options
+}
+
+// editedLines describes the lines changed by edits, e.g. "3-4, 10"
+func editedLines(edits []protocol.TextEdit) string {
+	type span struct{ start, end int }
+	var spans []span
+	for _, edit := range edits {
+		start, end := int(edit.Range.Start.Line)+1, int(edit.Range.End.Line)+1
+		// A range ending at the start of a line doesn't change that line
+		if edit.Range.End.Character == 0 && end > start {
+			end--
+		}
+		spans = append(spans, span{start, end})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var merged []span
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s.start <= merged[n-1].end+1 {
+			merged[n-1].end = max(merged[n-1].end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	parts := make([]string, len(merged))
+	for i, s := range merged {
+		if s.start == s.end {
+			parts[i] = fmt.Sprintf("%d", s.start)
+		} else {
+			parts[i] = fmt.Sprintf("%d-%d", s.start, s.end)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectFormattingOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    protocol.FormattingOptions
+	}{
+		{
+			name:    "Tabs",
+			content: "func main() {\n\tif true {\n\t\treturn\n\t}\n}\n",
+			want:    protocol.FormattingOptions{TabSize: 4, InsertSpaces: false},
+		},
+		{
+			name:    "Two spaces",
+			content: "def main():\n  if True:\n    return\n",
+			want:    protocol.FormattingOptions{TabSize: 2, InsertSpaces: true},
+		},
+		{
+			name:    "Block comment alignment is ignored",
+			content: "/**\n * Docs\n */\nfunction main() {\n    return;\n}\n",
+			want:    protocol.FormattingOptions{TabSize: 4, InsertSpaces: true},
+		},
+		{
+			name:    "No indentation",
+			content: "package main\n",
+			want:    protocol.FormattingOptions{TabSize: 4, InsertSpaces: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectFormattingOptions(tt.content))
+		})
+	}
+}
+
+func TestFormatOptionsApply(t *testing.T) {
+	tabSize := 8
+	insertSpaces := false
+	trim := true
+	options := FormatOptions{TabSize: &tabSize, InsertSpaces: &insertSpaces, TrimTrailingWhitespace: &trim}
+
+	assert.Equal(t, protocol.FormattingOptions{
+		TabSize:                8,
+		InsertSpaces:           false,
+		TrimTrailingWhitespace: true,
+	}, options.apply(protocol.FormattingOptions{TabSize: 2, InsertSpaces: true}))
+
+	// Unset fields keep the detected options
+	assert.Equal(t, protocol.FormattingOptions{TabSize: 2, InsertSpaces: true},
+		FormatOptions{}.apply(protocol.FormattingOptions{TabSize: 2, InsertSpaces: true}))
+}
+
+func TestEditedLines(t *testing.T) {
+	edits := []protocol.TextEdit{
+		{Range: lineRange(9, 9)},
+		{Range: protocol.Range{
+			Start: protocol.Position{Line: 2, Character: 0},
+			End:   protocol.Position{Line: 4, Character: 0},
+		}},
+		{Range: lineRange(3, 3)},
+		{Range: lineRange(11, 11)},
+	}
+	assert.Equal(t, "3-4, 10, 12", editedLines(edits))
+}
@@ -16,14 +16,17 @@ func GetCodeLens(ctx context.Context, client *lsp.Client, filePath string) (stri
 	if err != nil {
 		return "", fmt.Errorf("could not open file: %v", err)
 	}
-	// TODO: find a more appropriate way to wait
-	time.Sleep(time.Second)
 
 	// Create document identifier
 	docIdentifier := protocol.TextDocumentIdentifier{
 		URI: protocol.DocumentUri("file://" + filePath),
 	}
 
+	// Diagnostics arriving means the server has processed the file
+	if _, err := waitForDiagnostics(ctx, client, docIdentifier.URI, time.Second); err != nil {
+		return "", err
+	}
+
 	// Request code lens from LSP
 	params := protocol.CodeLensParams{
 		TextDocument: docIdentifier,
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// Kinds of location go_to_definition can navigate to
+const (
+	NavigateDefinition     = "definition"
+	NavigateTypeDefinition = "typeDefinition"
+	NavigateDeclaration    = "declaration"
+)
+
+// targetContextLines is the number of lines shown around a target whose
+// enclosing symbol could not be found
+const targetContextLines = 5
+
+// GoToDefinition returns the full source of the definition, type definition
+// or declaration of the symbol at a position. Unlike
+// ReadDefinitionAcrossServers it works for locals, fields and other symbols
+// the server doesn't report as workspace symbols, and for targets outside
+// the workspace such as dependencies.
+func GoToDefinition(ctx context.Context, client *lsp.Client, filePath string, line, column int, kind string) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	position := protocol.TextDocumentPositionParams{
+		TextDocument: protocol.TextDocumentIdentifier{
+			URI: protocol.DocumentUri("file://" + filePath),
+		},
+		Position: protocol.Position{
+			Line:      uint32(line - 1),
+			Character: uint32(column - 1),
+		},
+	}
+
+	var locations []protocol.Location
+	switch kind {
+	case "", NavigateDefinition:
+		kind = NavigateDefinition
+		result, err := client.Definition(ctx, protocol.DefinitionParams{TextDocumentPositionParams: position})
+		if err != nil {
+			return "", fmt.Errorf("failed to find definition: %v", err)
+		}
+		locations, err = result.Locations()
+		if err != nil {
+			return "", fmt.Errorf("failed to parse definition: %v", err)
+		}
+	case NavigateTypeDefinition:
+		result, err := client.TypeDefinition(ctx, protocol.TypeDefinitionParams{TextDocumentPositionParams: position})
+		if err != nil {
+			return "", fmt.Errorf("failed to find type definition: %v", err)
+		}
+		locations, err = result.Locations()
+		if err != nil {
+			return "", fmt.Errorf("failed to parse type definition: %v", err)
+		}
+	case NavigateDeclaration:
+		result, err := client.Declaration(ctx, protocol.DeclarationParams{TextDocumentPositionParams: position})
+		if err != nil {
+			return "", fmt.Errorf("failed to find declaration: %v", err)
+		}
+		locations, err = result.Locations()
+		if err != nil {
+			return "", fmt.Errorf("failed to parse declaration: %v", err)
+		}
+	default:
+		return "", fmt.Errorf("unknown kind %q, expected definition, typeDefinition or declaration", kind)
+	}
+
+	var targets []string
+	seen := make(map[protocol.Location]bool)
+	for _, loc := range locations {
+		if seen[loc] {
+			continue
+		}
+		seen[loc] = true
+
+		text, err := formatFullDefinition(ctx, client, loc, "", "")
+		if err != nil {
+			// Files in dependencies may not have symbols, so show the
+			// lines around the target instead
+			toolsLogger.Debug("Could not get full definition, showing context instead: %v", err)
+			text, err = formatTargetContext(loc, targetContextLines)
+			if err != nil {
+				toolsLogger.Error("Error reading %s target: %v", kind, err)
+				continue
+			}
+		}
+		targets = append(targets, text)
+	}
+
+	if len(targets) == 0 {
+		return fmt.Sprintf("No %s found at %s:%d:%d", kind, filePath, line, column) + indexingNotice(client), nil
+	}
+	return strings.Join(targets, ""), nil
+}
+
+// formatTargetContext returns the lines around a location with its file and
+// range, in the format of formatFullDefinition
+func formatTargetContext(loc protocol.Location, contextLines int) (string, error) {
+	content, err := os.ReadFile(loc.URI.Path())
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+
+	start := max(int(loc.Range.Start.Line)-contextLines, 0)
+	end := min(int(loc.Range.End.Line)+contextLines, len(lines)-1)
+	if start > end {
+		return "", fmt.Errorf("line number out of range")
+	}
+
+	locationInfo := fmt.Sprintf(
+		"File: %s\n"+
+			"Range: L%d:C%d - L%d:C%d\n\n",
+		strings.TrimPrefix(string(loc.URI), "file://"),
+		loc.Range.Start.Line+1,
+		loc.Range.Start.Character+1,
+		loc.Range.End.Line+1,
+		loc.Range.End.Character+1,
+	)
+
+	text := addLineNumbers(strings.Join(lines[start:end+1], "\n"), start+1)
+	return "---\n\n" + locationInfo + text + "\n", nil
+}
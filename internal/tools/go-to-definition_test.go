@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTargetContext(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lib.py")
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, "line "+strings.Repeat("x", i%3))
+	}
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644))
+
+	loc := protocol.Location{
+		URI:   protocol.DocumentUri("file://" + path),
+		Range: protocol.Range{Start: protocol.Position{Line: 1, Character: 2}, End: protocol.Position{Line: 1, Character: 6}},
+	}
+	text, err := formatTargetContext(loc, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "---\n\n"+
+		"File: "+path+"\n"+
+		"Range: L2:C3 - L2:C7\n\n"+
+		"1|line x\n"+
+		"2|line xx\n"+
+		"3|line \n"+
+		"4|line x\n\n", text)
+
+	loc.Range.Start.Line, loc.Range.End.Line = 40, 40
+	_, err = formatTargetContext(loc, 2)
+	assert.Error(t, err)
+}
+
+func TestNavigationLocations(t *testing.T) {
+	location := protocol.Location{URI: "file:///go/pkg/mod/lib/lib.go", Range: lineRange(9, 9)}
+	link := `[{
+		"targetUri": "file:///go/pkg/mod/lib/lib.go",
+		"targetRange": {"start": {"line": 8, "character": 0}, "end": {"line": 12, "character": 1}},
+		"targetSelectionRange": {"start": {"line": 9, "character": 0}, "end": {"line": 9, "character": 1}}
+	}]`
+	encoded, err := json.Marshal(location)
+	require.NoError(t, err)
+
+	for _, response := range []string{string(encoded), "[" + string(encoded) + "]", link} {
+		var definition protocol.Or_Result_textDocument_definition
+		require.NoError(t, json.Unmarshal([]byte(response), &definition))
+		locations, err := definition.Locations()
+		require.NoError(t, err)
+		assert.Equal(t, []protocol.Location{location}, locations, response)
+
+		var typeDefinition protocol.Or_Result_textDocument_typeDefinition
+		require.NoError(t, json.Unmarshal([]byte(response), &typeDefinition))
+		locations, err = typeDefinition.Locations()
+		require.NoError(t, err)
+		assert.Equal(t, []protocol.Location{location}, locations, response)
+
+		var declaration protocol.Or_Result_textDocument_declaration
+		require.NoError(t, json.Unmarshal([]byte(response), &declaration))
+		locations, err = declaration.Locations()
+		require.NoError(t, err)
+		assert.Equal(t, []protocol.Location{location}, locations, response)
+	}
+}
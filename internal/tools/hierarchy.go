@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// hierarchyItem holds the fields of a call or type hierarchy item that are
+// shown in a tree
+type hierarchyItem struct {
+	name      string
+	kind      protocol.SymbolKind
+	detail    string
+	uri       protocol.DocumentUri
+	selection protocol.Range
+}
+
+// describe formats an item as its kind, name, detail and location
+func (i hierarchyItem) describe(workspaceDir string) string {
+	text := fmt.Sprintf("%s %s", protocol.TableKindMap[i.kind], i.name)
+	if i.detail != "" {
+		text += fmt.Sprintf(" (%s)", strings.ReplaceAll(i.detail, "\n", " "))
+	}
+	return fmt.Sprintf("%s - %s:%d", text, displayPath(i.uri, workspaceDir), i.selection.Start.Line+1)
+}
+
+// key identifies an item independently of how it was reached
+func (i hierarchyItem) key() string {
+	return fmt.Sprintf("%s:%d:%d", i.uri, i.selection.Start.Line, i.selection.Start.Character)
+}
+
+// hierarchyDirections checks the direction and depth given to a hierarchy
+// tool. It returns which of the two directions to render, with "both"
+// selecting each of them, and the depth clamped to maxDepth.
+func hierarchyDirections(direction, defaultDirection string, directions [2]string, depth, defaultDepth, maxDepth int) ([]string, int, error) {
+	if direction == "" {
+		direction = defaultDirection
+	}
+	var selected []string
+	for _, dir := range directions {
+		if direction == dir || direction == "both" {
+			selected = append(selected, dir)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, 0, fmt.Errorf("unknown direction %q, expected %s, %s or both", direction, directions[0], directions[1])
+	}
+
+	if depth <= 0 {
+		depth = defaultDepth
+	}
+	return selected, min(depth, maxDepth), nil
+}
+
+// renderHierarchy writes the tree of each root in each direction, one after
+// the other
+func renderHierarchy[T any](roots []T, directions []string, newWriter func(direction string) *treeWriter[T]) (string, error) {
+	var output strings.Builder
+	for _, root := range roots {
+		for _, dir := range directions {
+			w := newWriter(dir)
+			if err := w.write(root); err != nil {
+				return "", err
+			}
+			output.WriteString(w.output.String() + "\n")
+		}
+	}
+	return output.String(), nil
+}
+
+// treeWriter renders a hierarchy in one direction, starting from a root and
+// following the related nodes of each node up to maxDepth levels
+type treeWriter[T any] struct {
+	// header is written before the root, e.g. "Subtypes of "
+	header string
+
+	// related fetches the nodes one level below a node
+	related func(T) ([]T, error)
+
+	// item returns the hierarchy item of a node
+	item func(T) hierarchyItem
+
+	// details optionally returns extra lines to show under a node
+	details func(T) []string
+
+	// prefix is written before each related node, e.g. an arrow
+	prefix string
+
+	// cycleNote marks a node already on the path from the root, and
+	// expandedNote one whose related nodes are listed earlier in the tree
+	cycleNote    string
+	expandedNote string
+
+	// noun names what is listed in the message shown when maxItems is reached
+	noun string
+
+	maxItems     int
+	maxDepth     int
+	workspaceDir string
+
+	output strings.Builder
+
+	// Nodes whose related nodes have already been listed
+	expanded map[string]bool
+
+	// Number of nodes listed so far
+	count int
+}
+
+// write renders the tree of a root node
+func (w *treeWriter[T]) write(root T) error {
+	w.expanded = make(map[string]bool)
+
+	item := w.item(root)
+	w.output.WriteString(w.header + item.describe(w.workspaceDir) + "\n")
+	return w.writeRelated(root, 1, map[string]bool{item.key(): true})
+}
+
+// writeRelated lists the nodes related to a node, then recurses into each of
+// them. path holds the nodes leading to this one so that cycles are not
+// followed.
+func (w *treeWriter[T]) writeRelated(node T, depth int, path map[string]bool) error {
+	w.expanded[w.item(node).key()] = true
+
+	related, err := w.related(node)
+	if err != nil {
+		return err
+	}
+
+	indent := strings.Repeat("  ", depth)
+	if len(related) == 0 && depth == 1 {
+		w.output.WriteString(indent + "(none)\n")
+	}
+
+	// Details line up with the description, one level further in
+	detailIndent := indent + strings.Repeat(" ", len(w.prefix)) + "  "
+
+	for _, rel := range related {
+		if w.count >= w.maxItems {
+			w.output.WriteString(fmt.Sprintf("%s... more %s not shown (limit of %d reached)\n", indent, w.noun, w.maxItems))
+			return nil
+		}
+		w.count++
+
+		item := w.item(rel)
+		key := item.key()
+		note := ""
+		recurse := depth < w.maxDepth
+		switch {
+		case path[key]:
+			note = w.cycleNote
+			recurse = false
+		case w.expanded[key] && recurse:
+			note = w.expandedNote
+			recurse = false
+		}
+
+		w.output.WriteString(fmt.Sprintf("%s%s%s%s\n", indent, w.prefix, item.describe(w.workspaceDir), note))
+		if w.details != nil {
+			for _, line := range w.details(rel) {
+				w.output.WriteString(detailIndent + line + "\n")
+			}
+		}
+
+		if recurse {
+			path[key] = true
+			err := w.writeRelated(rel, depth+1, path)
+			delete(path, key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
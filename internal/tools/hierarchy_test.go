@@ -0,0 +1,25 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHierarchyDirections(t *testing.T) {
+	directions := [2]string{CallsIncoming, CallsOutgoing}
+
+	selected, depth, err := hierarchyDirections("", CallsIncoming, directions, 0, 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{CallsIncoming}, selected)
+	assert.Equal(t, 1, depth)
+
+	selected, depth, err = hierarchyDirections(CallsBoth, CallsIncoming, directions, 9, 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{CallsIncoming, CallsOutgoing}, selected)
+	assert.Equal(t, 5, depth)
+
+	_, _, err = hierarchyDirections("sideways", CallsIncoming, directions, 1, 1, 5)
+	assert.EqualError(t, err, `unknown direction "sideways", expected incoming, outgoing or both`)
+}
@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// FindImplementations returns the full source of every implementation of the
+// interface or abstract method at a position
+func FindImplementations(ctx context.Context, client *lsp.Client, filePath string, line, column int) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	position := protocol.Position{
+		Line:      uint32(line - 1),
+		Character: uint32(column - 1),
+	}
+	uri := protocol.DocumentUri("file://" + filePath)
+
+	implementations, err := implementationsAt(ctx, client, uri, position, make(map[string]bool))
+	if err != nil {
+		return "", err
+	}
+	if len(implementations) == 0 {
+		return fmt.Sprintf("No implementations found at %s:%d:%d", filePath, line, column) + indexingNotice(client), nil
+	}
+	return strings.Join(implementations, ""), nil
+}
+
+// FindImplementationsForSymbol returns the full source of every
+// implementation of the interfaces or abstract methods with the given name
+func FindImplementationsForSymbol(ctx context.Context, clients []*lsp.Client, symbolName string) (string, error) {
+	positions, err := findSymbolPositions(ctx, clients, symbolName)
+	if err != nil {
+		return "", err
+	}
+	if len(positions) == 0 {
+		return fmt.Sprintf("%s not found", symbolName) + indexingNotice(clients...), nil
+	}
+
+	var implementations []string
+	seen := make(map[string]bool)
+	for _, pos := range positions {
+		found, err := implementationsAt(ctx, pos.client, pos.uri, pos.position, seen)
+		if err != nil {
+			return "", err
+		}
+		implementations = append(implementations, found...)
+	}
+
+	if len(implementations) == 0 {
+		return fmt.Sprintf("No implementations of %s found", symbolName) + indexingNotice(clients...), nil
+	}
+	return strings.Join(implementations, ""), nil
+}
+
+// implementationsAt returns the formatted implementations of the symbol at a
+// position. Locations in seen are skipped, and the ones returned are added.
+func implementationsAt(ctx context.Context, client *lsp.Client, uri protocol.DocumentUri, position protocol.Position, seen map[string]bool) ([]string, error) {
+	result, err := client.Implementation(ctx, protocol.ImplementationParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     position,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find implementations: %v", err)
+	}
+
+	locations, err := result.Locations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse implementations: %v", err)
+	}
+
+	var implementations []string
+	for _, loc := range locations {
+		key := fmt.Sprintf("%s:%d:%d", loc.URI, loc.Range.Start.Line, loc.Range.Start.Character)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		text, err := formatFullDefinition(ctx, client, loc, "", "")
+		if err != nil {
+			toolsLogger.Error("Error getting implementation: %v", err)
+			continue
+		}
+		implementations = append(implementations, text)
+	}
+	return implementations, nil
+}
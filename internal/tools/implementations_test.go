@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplementationLocations(t *testing.T) {
+	want := protocol.Location{
+		URI:   "file:///src/circle.go",
+		Range: protocol.Range{Start: protocol.Position{Line: 4, Character: 5}, End: protocol.Position{Line: 4, Character: 11}},
+	}
+	location := `{"uri": "file:///src/circle.go", "range": {"start": {"line": 4, "character": 5}, "end": {"line": 4, "character": 11}}}`
+	link := `{
+		"targetUri": "file:///src/circle.go",
+		"targetRange": {"start": {"line": 4, "character": 0}, "end": {"line": 6, "character": 1}},
+		"targetSelectionRange": {"start": {"line": 4, "character": 5}, "end": {"line": 4, "character": 11}}
+	}`
+
+	tests := []struct {
+		name     string
+		response string
+		want     []protocol.Location
+	}{
+		{"Single location", location, []protocol.Location{want}},
+		{"Location array", "[" + location + "," + location + "]", []protocol.Location{want, want}},
+		{"Location links use the selection range", "[" + link + "]", []protocol.Location{want}},
+		{"Empty array", "[]", []protocol.Location{}},
+		{"Null", "null", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result protocol.Or_Result_textDocument_implementation
+			require.NoError(t, json.Unmarshal([]byte(tt.response), &result))
+			locations, err := result.Locations()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, locations)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// GetInlayHints returns lines of a file with the server's inlay hints, such
+// as inferred types and parameter names, written into the source between
+// « and ». If startLine is not positive the whole file is shown.
+func GetInlayHints(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine int) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	// A final newline doesn't start another line
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if startLine <= 0 {
+		startLine, endLine = 1, len(lines)
+	}
+	if endLine < startLine {
+		endLine = startLine
+	}
+	if startLine > len(lines) {
+		return "", fmt.Errorf("start line %d is past the end of the file (%d lines)", startLine, len(lines))
+	}
+	endLine = min(endLine, len(lines))
+
+	hints, err := client.InlayHint(ctx, protocol.InlayHintParams{
+		TextDocument: protocol.TextDocumentIdentifier{
+			URI: protocol.DocumentUri("file://" + filePath),
+		},
+		Range: protocol.Range{
+			Start: protocol.Position{Line: uint32(startLine - 1)},
+			End:   protocol.Position{Line: uint32(endLine - 1), Character: utf16Offset(lines[endLine-1], len(lines[endLine-1]))},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get inlay hints: %v", err)
+	}
+
+	region := lines[startLine-1 : endLine]
+	annotated, count := annotateLines(region, startLine-1, hints)
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "%s, lines %d-%d", filepath.Base(filePath), startLine, endLine)
+	if count == 0 {
+		result.WriteString(", no inlay hints. The server may need inlay hints enabled in its settings.\n\n")
+	} else {
+		fmt.Fprintf(&result, ", %d inlay hints in «»:\n\n", count)
+	}
+	result.WriteString(addLineNumbers(strings.Join(annotated, "\n"), startLine))
+	return result.String(), nil
+}
+
+// annotateLines writes hints into lines, the first of which is firstLine
+// (0-indexed). Hints outside the lines are ignored. It returns the annotated
+// lines and the number of hints written.
+func annotateLines(lines []string, firstLine int, hints []protocol.InlayHint) ([]string, int) {
+	byLine := make(map[int][]protocol.InlayHint)
+	for _, hint := range hints {
+		i := int(hint.Position.Line) - firstLine
+		if i < 0 || i >= len(lines) {
+			continue
+		}
+		byLine[i] = append(byLine[i], hint)
+	}
+
+	count := 0
+	annotated := make([]string, len(lines))
+	for i, line := range lines {
+		lineHints := byLine[i]
+		// Hints at the same position keep the server's order
+		sort.SliceStable(lineHints, func(a, b int) bool {
+			return lineHints[a].Position.Character < lineHints[b].Position.Character
+		})
+
+		var text strings.Builder
+		written := 0
+		for _, hint := range lineHints {
+			label := hint.Label.Text()
+			if label == "" {
+				continue
+			}
+			at := byteOffset(line, hint.Position.Character)
+			text.WriteString(line[written:at])
+			written = at
+
+			if hint.PaddingLeft {
+				text.WriteString(" ")
+			}
+			text.WriteString("«" + label + "»")
+			if hint.PaddingRight {
+				text.WriteString(" ")
+			}
+			count++
+		}
+		text.WriteString(line[written:])
+		annotated[i] = text.String()
+	}
+	return annotated, count
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotateLines(t *testing.T) {
+	hint := func(line, character uint32, label any, kind protocol.InlayHintKind) protocol.InlayHint {
+		return protocol.InlayHint{
+			Position:     protocol.Position{Line: line, Character: character},
+			Label:        protocol.Or_InlayHint_label{Value: label},
+			Kind:         kind,
+			PaddingLeft:  kind == protocol.Type,
+			PaddingRight: kind == protocol.Parameter,
+		}
+	}
+
+	lines := []string{
+		"\tcount := compute(5, true)",
+		"\tfor i, v := range items {",
+		"\t}",
+	}
+	hints := []protocol.InlayHint{
+		hint(10, 18, "limit:", protocol.Parameter),
+		hint(10, 21, "verbose:", protocol.Parameter),
+		hint(10, 6, []protocol.InlayHintLabelPart{{Value: "map[string]"}, {Value: "int"}}, protocol.Type),
+		hint(11, 6, "int", protocol.Type),
+		hint(11, 9, "Item", protocol.Type),
+		// Outside the lines
+		hint(3, 0, "ignored", protocol.Type),
+	}
+
+	annotated, count := annotateLines(lines, 10, hints)
+	assert.Equal(t, 5, count)
+	assert.Equal(t, []string{
+		"\tcount «map[string]int» := compute(«limit:» 5, «verbose:» true)",
+		"\tfor i «int», v «Item» := range items {",
+		"\t}",
+	}, annotated)
+
+	// Hint positions count UTF-16 units, not bytes
+	annotated, count = annotateLines([]string{"\tgrüße := greet(\"日本\", true)"}, 0, []protocol.InlayHint{
+		hint(0, 6, "string", protocol.Type),
+		hint(0, 16, "name:", protocol.Parameter),
+		hint(0, 22, "formal:", protocol.Parameter),
+	})
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"\tgrüße «string» := greet(«name:» \"日本\", «formal:» true)"}, annotated)
+}
@@ -5,12 +5,258 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/isaacphi/mcp-language-server/internal/lsp"
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
+// indexingNotice returns a note to append to empty results while a server
+// is still indexing, or an empty string once they are all ready
+func indexingNotice(clients ...*lsp.Client) string {
+	for _, client := range clients {
+		if notice := clientIndexingNotice(client); notice != "" {
+			return notice
+		}
+	}
+	return ""
+}
+
+func clientIndexingNotice(client *lsp.Client) string {
+	state := client.IndexingState()
+	if !state.Busy() {
+		return ""
+	}
+
+	if len(state.Active) == 0 {
+		return "\n\nNote: the language server is still starting up, results may be incomplete."
+	}
+
+	var active []string
+	for _, p := range state.Active {
+		active = append(active, p.String())
+	}
+	return fmt.Sprintf("\n\nNote: the language server is still indexing (%s), results may be incomplete.",
+		strings.Join(active, "; "))
+}
+
+// matchesSymbolName reports whether a workspace symbol is the one named by
+// symbolName rather than one of the fuzzy matches workspace/symbol returns
+func matchesSymbolName(profile *lsp.Profile, symbolName string, symbol protocol.WorkspaceSymbolResult) bool {
+	var kind protocol.SymbolKind
+	var containerName string
+	switch v := symbol.(type) {
+	case *protocol.SymbolInformation:
+		kind, containerName = v.Kind, v.ContainerName
+	case *protocol.WorkspaceSymbol:
+		kind, containerName = v.Kind, v.ContainerName
+	}
+
+	name := symbol.GetName()
+	if name == symbolName {
+		return true
+	}
+
+	// Handle different matching strategies based on the search term
+	if container, member, ok := profile.SplitSymbolName(symbolName); ok {
+		// For qualified names like "Type.Method", servers that don't qualify
+		// names report the type as the container
+		return name == member && strings.HasSuffix(containerName, container)
+	}
+
+	// For unqualified method names, also match Type.symbolName
+	return kind == protocol.Method && hasQualifiedSuffix(profile, name, symbolName)
+}
+
+// displayPath returns the path of a document relative to the workspace, or
+// the absolute path for documents outside it
+func displayPath(uri protocol.DocumentUri, workspaceDir string) string {
+	path := strings.TrimPrefix(string(uri), "file://")
+	if rel, err := filepath.Rel(workspaceDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// symbolPosition is where a symbol's name appears in a document
+type symbolPosition struct {
+	client   *lsp.Client
+	name     string
+	kind     protocol.SymbolKind
+	uri      protocol.DocumentUri
+	position protocol.Position
+}
+
+// fromEachClient calls fn for every client and merges the results. Clients
+// that fail are logged and skipped, and an error is only returned if all of
+// them fail. operation describes fn in the log, e.g. "Definition lookup".
+func fromEachClient[T any](clients []*lsp.Client, operation string, fn func(*lsp.Client) ([]T, error)) ([]T, error) {
+	var results []T
+	var firstErr error
+	failed := 0
+	for _, client := range clients {
+		found, err := fn(client)
+		if err != nil {
+			toolsLogger.Warn("%s failed for one language server: %v", operation, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+		results = append(results, found...)
+	}
+
+	if failed > 0 && failed == len(clients) {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// findSymbolPositions looks up a symbol by name in every server and returns
+// the position of its name in each declaration found. The documents are
+// opened so position based requests can be made.
+func findSymbolPositions(ctx context.Context, clients []*lsp.Client, symbolName string) ([]symbolPosition, error) {
+	return fromEachClient(clients, "Symbol lookup", func(client *lsp.Client) ([]symbolPosition, error) {
+		return lookupSymbolPositions(ctx, client, symbolName)
+	})
+}
+
+// lookupSymbolPositions returns the positions of a symbol's declarations
+// found by one language server
+func lookupSymbolPositions(ctx context.Context, client *lsp.Client, symbolName string) ([]symbolPosition, error) {
+	symbolResult, err := client.Symbol(ctx, protocol.WorkspaceSymbolParams{
+		Query: symbolName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch symbol: %v", err)
+	}
+
+	results, err := symbolResult.Results()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse results: %v", err)
+	}
+
+	var positions []symbolPosition
+	profile := client.Profile()
+	for _, symbol := range results {
+		if !matchesSymbolName(profile, symbolName, symbol) {
+			continue
+		}
+
+		loc := symbol.GetLocation()
+		if err := client.OpenFile(ctx, loc.URI.Path()); err != nil {
+			toolsLogger.Error("Error opening file: %v", err)
+			continue
+		}
+
+		_, member, _ := profile.SplitSymbolName(symbolName)
+		position := symbolPosition{
+			client:   client,
+			name:     symbol.GetName(),
+			uri:      loc.URI,
+			position: identifierPosition(loc, member),
+		}
+		switch v := symbol.(type) {
+		case *protocol.SymbolInformation:
+			position.kind = v.Kind
+		case *protocol.WorkspaceSymbol:
+			position.kind = v.Kind
+		}
+		positions = append(positions, position)
+	}
+	return positions, nil
+}
+
+// syncFile opens a file, or sends its current content if it is already open.
+// Edits computed by the server are relative to its copy of the file, so it
+// must have seen changes made since the file was opened.
+func syncFile(ctx context.Context, client *lsp.Client, filePath string) error {
+	if client.IsFileOpen(filePath) {
+		if err := client.NotifyChange(ctx, filePath); err != nil {
+			return fmt.Errorf("could not update file: %v", err)
+		}
+		return nil
+	}
+	if err := client.OpenFile(ctx, filePath); err != nil {
+		return fmt.Errorf("could not open file: %v", err)
+	}
+	return nil
+}
+
+// identifierPosition returns the position of name within a symbol's range.
+// Symbol ranges often start at a keyword such as "func" or "class", while
+// position based requests need a position on the name itself.
+func identifierPosition(loc protocol.Location, name string) protocol.Position {
+	content, err := os.ReadFile(loc.URI.Path())
+	if err != nil {
+		return loc.Range.Start
+	}
+	return findIdentifier(strings.Split(string(content), "\n"), loc.Range, name)
+}
+
+// findIdentifier returns the position of the first whole identifier name in
+// rng, or the start of rng if it isn't found. A match that is part of a
+// longer identifier, such as Serve in Server, is skipped.
+func findIdentifier(lines []string, rng protocol.Range, name string) protocol.Position {
+	if name == "" {
+		return rng.Start
+	}
+	for line := rng.Start.Line; line <= rng.End.Line && int(line) < len(lines); line++ {
+		text := lines[line]
+		offset := 0
+		if line == rng.Start.Line {
+			offset = byteOffset(text, rng.Start.Character)
+		}
+		for {
+			i := strings.Index(text[offset:], name)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(name)
+			before, _ := utf8.DecodeLastRuneInString(text[:start])
+			after, _ := utf8.DecodeRuneInString(text[end:])
+			if !isIdentifierRune(before) && !isIdentifierRune(after) {
+				return protocol.Position{Line: line, Character: utf16Offset(text, start)}
+			}
+			offset = start + 1
+		}
+	}
+	return rng.Start
+}
+
+// isIdentifierRune reports whether r can be part of an identifier
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// utf16Offset converts a byte offset in a line to an LSP character offset,
+// which counts UTF-16 code units
+func utf16Offset(text string, offset int) uint32 {
+	var units uint32
+	for _, r := range text[:min(offset, len(text))] {
+		units += uint32(utf16.RuneLen(r))
+	}
+	return units
+}
+
+// byteOffset converts an LSP character offset in a line to a byte offset.
+// Offsets past the end of the line return the line's length.
+func byteOffset(text string, character uint32) int {
+	var units uint32
+	for i, r := range text {
+		if units >= character {
+			return i
+		}
+		units += uint32(utf16.RuneLen(r))
+	}
+	return len(text)
+}
+
 // Gets the full code block surrounding the start of the input location
 func GetFullDefinition(ctx context.Context, client *lsp.Client, startLocation protocol.Location) (string, protocol.Location, error) {
 	symParams := protocol.DocumentSymbolParams{
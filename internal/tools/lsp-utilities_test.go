@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindIdentifier(t *testing.T) {
+	lines := []string{
+		"// Serve starts the server",
+		"func (s *Server) Serve(addr string) error {",
+		"\treturn nil",
+		"}",
+		`func (é *Ünïcode) Serve() {}`,
+	}
+	rng := func(startLine, startChar, endLine uint32) protocol.Range {
+		return protocol.Range{
+			Start: protocol.Position{Line: startLine, Character: startChar},
+			End:   protocol.Position{Line: endLine},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		rng        protocol.Range
+		identifier string
+		want       protocol.Position
+	}{
+		{"Skips longer identifiers", rng(1, 0, 3), "Serve", protocol.Position{Line: 1, Character: 17}},
+		{"Starts at the range", rng(0, 3, 3), "Serve", protocol.Position{Line: 0, Character: 3}},
+		{"Whole identifier", rng(1, 0, 3), "Server", protocol.Position{Line: 1, Character: 9}},
+		{"Later line", rng(1, 0, 3), "nil", protocol.Position{Line: 2, Character: 8}},
+		{"UTF-16 character", rng(4, 0, 4), "Serve", protocol.Position{Line: 4, Character: 18}},
+		{"UTF-16 range start", rng(4, 7, 4), "Serve", protocol.Position{Line: 4, Character: 18}},
+		{"Not found", rng(1, 4, 3), "Handle", protocol.Position{Line: 1, Character: 4}},
+		{"Empty name", rng(1, 4, 3), "", protocol.Position{Line: 1, Character: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, findIdentifier(lines, tt.rng, tt.identifier))
+		})
+	}
+}
+
+func TestUTF16Offsets(t *testing.T) {
+	// "é" is two bytes and one UTF-16 unit, "𐐀" is four bytes and two units
+	text := "aé𐐀b"
+
+	assert.Equal(t, uint32(0), utf16Offset(text, 0))
+	assert.Equal(t, uint32(2), utf16Offset(text, 3))
+	assert.Equal(t, uint32(4), utf16Offset(text, 7))
+	assert.Equal(t, uint32(5), utf16Offset(text, 100))
+
+	assert.Equal(t, 0, byteOffset(text, 0))
+	assert.Equal(t, 3, byteOffset(text, 2))
+	assert.Equal(t, 7, byteOffset(text, 4))
+	assert.Equal(t, 8, byteOffset(text, 100))
+}
+
+func TestFromEachClient(t *testing.T) {
+	working, broken := &lsp.Client{}, &lsp.Client{}
+	errBroken := errors.New("server crashed")
+	lookup := func(client *lsp.Client) ([]string, error) {
+		if client == broken {
+			return nil, errBroken
+		}
+		return []string{"found"}, nil
+	}
+
+	results, err := fromEachClient([]*lsp.Client{broken, working, working}, "Lookup", lookup)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"found", "found"}, results)
+
+	_, err = fromEachClient([]*lsp.Client{broken, broken}, "Lookup", lookup)
+	assert.ErrorIs(t, err, errBroken)
+
+	results, err = fromEachClient(nil, "Lookup", lookup)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
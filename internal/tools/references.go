@@ -12,36 +12,47 @@ import (
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
-func FindReferences(ctx context.Context, client *lsp.Client, symbolName string) (string, error) {
-	// Get context lines from environment variable
-	contextLines := 5
-	if envLines := os.Getenv("LSP_CONTEXT_LINES"); envLines != "" {
-		if val, err := strconv.Atoi(envLines); err == nil && val >= 0 {
-			contextLines = val
-		}
+// FindReferencesAcrossServers finds references to a symbol with every
+// language server and merges them
+func FindReferencesAcrossServers(ctx context.Context, clients []*lsp.Client, symbolName string) (string, error) {
+	allReferences, err := fromEachClient(clients, "References lookup", func(client *lsp.Client) ([]string, error) {
+		return findReferences(ctx, client, symbolName)
+	})
+	if err != nil {
+		return "", err
 	}
 
-	// First get the symbol location like ReadDefinition does
+	if len(allReferences) == 0 {
+		return fmt.Sprintf("No references found for symbol: %s", symbolName) + indexingNotice(clients...), nil
+	}
+
+	return strings.Join(allReferences, "\n"), nil
+}
+
+// findReferences returns the formatted references to a symbol, grouped by
+// file, found by one language server
+func findReferences(ctx context.Context, client *lsp.Client, symbolName string) ([]string, error) {
+	contextLines := referenceContextLines()
+
+	// First get the symbol location like findDefinitions does
 	symbolResult, err := client.Symbol(ctx, protocol.WorkspaceSymbolParams{
 		Query: symbolName,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch symbol: %v", err)
+		return nil, fmt.Errorf("failed to fetch symbol: %v", err)
 	}
 
 	results, err := symbolResult.Results()
 	if err != nil {
-		return "", fmt.Errorf("failed to parse results: %v", err)
+		return nil, fmt.Errorf("failed to parse results: %v", err)
 	}
 
+	profile := client.Profile()
 	var allReferences []string
 	for _, symbol := range results {
 		// Handle different matching strategies based on the search term
-		if strings.Contains(symbolName, ".") {
-			// For qualified names like "Type.Method", check for various matches
-			parts := strings.Split(symbolName, ".")
-			methodName := parts[len(parts)-1]
-
+		if _, methodName, ok := profile.SplitSymbolName(symbolName); ok {
+			// For qualified names like "Type.Method", check for various matches.
 			// Try matching the unqualified method name for languages that don't use qualified names in symbols
 			if symbol.GetName() != symbolName && symbol.GetName() != methodName {
 				continue
@@ -74,78 +85,129 @@ func FindReferences(ctx context.Context, client *lsp.Client, symbolName string)
 		}
 		refs, err := client.References(ctx, refsParams)
 		if err != nil {
-			return "", fmt.Errorf("failed to get references: %v", err)
+			return nil, fmt.Errorf("failed to get references: %v", err)
 		}
 
-		// Group references by file
-		refsByFile := make(map[protocol.DocumentUri][]protocol.Location)
-		for _, ref := range refs {
-			refsByFile[ref.URI] = append(refsByFile[ref.URI], ref)
-		}
+		allReferences = append(allReferences, formatReferences(ctx, client, refs, contextLines)...)
+	}
+
+	return allReferences, nil
+}
+
+// FindReferencesAt finds the references to the symbol at a position. Unlike
+// FindReferencesAcrossServers it works for locals, fields and other symbols
+// the server doesn't report as workspace symbols.
+func FindReferencesAt(ctx context.Context, client *lsp.Client, filePath string, line, column int, includeDeclaration bool) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	refs, err := client.References(ctx, protocol.ReferenceParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{
+				URI: protocol.DocumentUri("file://" + filePath),
+			},
+			Position: protocol.Position{
+				Line:      uint32(line - 1),
+				Character: uint32(column - 1),
+			},
+		},
+		Context: protocol.ReferenceContext{
+			IncludeDeclaration: includeDeclaration,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get references: %v", err)
+	}
+
+	if len(refs) == 0 {
+		return fmt.Sprintf("No references found for the symbol at %s:%d:%d", filePath, line, column) + indexingNotice(client), nil
+	}
 
-		// Get sorted list of URIs
-		uris := make([]string, 0, len(refsByFile))
-		for uri := range refsByFile {
-			uris = append(uris, string(uri))
+	return strings.Join(formatReferences(ctx, client, refs, referenceContextLines()), "\n"), nil
+}
+
+// referenceContextLines returns the number of lines shown around each
+// reference, set by LSP_CONTEXT_LINES
+func referenceContextLines() int {
+	contextLines := 5
+	if envLines := os.Getenv("LSP_CONTEXT_LINES"); envLines != "" {
+		if val, err := strconv.Atoi(envLines); err == nil && val >= 0 {
+			contextLines = val
 		}
-		sort.Strings(uris)
-
-		// Process each file's references in sorted order
-		for _, uriStr := range uris {
-			uri := protocol.DocumentUri(uriStr)
-			fileRefs := refsByFile[uri]
-			filePath := strings.TrimPrefix(uriStr, "file://")
-
-			// Format file header
-			fileInfo := fmt.Sprintf("---\n\n%s\nReferences in File: %d\n",
-				filePath,
-				len(fileRefs),
-			)
-
-			// Format locations with context
-			fileContent, err := os.ReadFile(filePath)
-			if err != nil {
-				// Log error but continue with other files
-				allReferences = append(allReferences, fileInfo+"\nError reading file: "+err.Error())
-				continue
-			}
+	}
+	return contextLines
+}
 
-			lines := strings.Split(string(fileContent), "\n")
+// formatReferences groups references by file and formats each file's
+// references with the lines around them
+func formatReferences(ctx context.Context, client *lsp.Client, refs []protocol.Location, contextLines int) []string {
+	var allReferences []string
 
-			// Track reference locations for header display
-			var locStrings []string
-			for _, ref := range fileRefs {
-				locStr := fmt.Sprintf("L%d:C%d",
-					ref.Range.Start.Line+1,
-					ref.Range.Start.Character+1)
-				locStrings = append(locStrings, locStr)
-			}
+	// Group references by file
+	refsByFile := make(map[protocol.DocumentUri][]protocol.Location)
+	for _, ref := range refs {
+		refsByFile[ref.URI] = append(refsByFile[ref.URI], ref)
+	}
 
-			// Collect lines to display using the utility function
-			linesToShow, err := GetLineRangesToDisplay(ctx, client, fileRefs, len(lines), contextLines)
-			if err != nil {
-				// Log error but continue with other files
-				continue
-			}
+	// Get sorted list of URIs
+	uris := make([]string, 0, len(refsByFile))
+	for uri := range refsByFile {
+		uris = append(uris, string(uri))
+	}
+	sort.Strings(uris)
+
+	// Process each file's references in sorted order
+	for _, uriStr := range uris {
+		uri := protocol.DocumentUri(uriStr)
+		fileRefs := refsByFile[uri]
+		filePath := strings.TrimPrefix(uriStr, "file://")
+
+		// Format file header
+		fileInfo := fmt.Sprintf("---\n\n%s\nReferences in File: %d\n",
+			filePath,
+			len(fileRefs),
+		)
+
+		// Format locations with context
+		fileContent, err := os.ReadFile(filePath)
+		if err != nil {
+			// Log error but continue with other files
+			allReferences = append(allReferences, fileInfo+"\nError reading file: "+err.Error())
+			continue
+		}
 
-			// Convert to line ranges using the utility function
-			lineRanges := ConvertLinesToRanges(linesToShow, len(lines))
+		lines := strings.Split(string(fileContent), "\n")
 
-			// Format with locations in header
-			formattedOutput := fileInfo
-			if len(locStrings) > 0 {
-				formattedOutput += "At: " + strings.Join(locStrings, ", ") + "\n"
-			}
+		// Track reference locations for header display
+		var locStrings []string
+		for _, ref := range fileRefs {
+			locStr := fmt.Sprintf("L%d:C%d",
+				ref.Range.Start.Line+1,
+				ref.Range.Start.Character+1)
+			locStrings = append(locStrings, locStr)
+		}
+
+		// Collect lines to display using the utility function
+		linesToShow, err := GetLineRangesToDisplay(ctx, client, fileRefs, len(lines), contextLines)
+		if err != nil {
+			// Log error but continue with other files
+			continue
+		}
+
+		// Convert to line ranges using the utility function
+		lineRanges := ConvertLinesToRanges(linesToShow, len(lines))
 
-			// Format the content with ranges
-			formattedOutput += "\n" + FormatLinesWithRanges(lines, lineRanges)
-			allReferences = append(allReferences, formattedOutput)
+		// Format with locations in header
+		formattedOutput := fileInfo
+		if len(locStrings) > 0 {
+			formattedOutput += "At: " + strings.Join(locStrings, ", ") + "\n"
 		}
-	}
 
-	if len(allReferences) == 0 {
-		return fmt.Sprintf("No references found for symbol: %s", symbolName), nil
+		// Format the content with ranges
+		formattedOutput += "\n" + FormatLinesWithRanges(lines, lineRanges)
+		allReferences = append(allReferences, formattedOutput)
 	}
 
-	return strings.Join(allReferences, "\n"), nil
+	return allReferences
 }
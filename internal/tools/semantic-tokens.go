@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// semanticToken is a decoded semantic token. Positions are 0-indexed.
+type semanticToken struct {
+	line      uint32
+	start     uint32
+	length    uint32
+	tokenType string
+	modifiers []string
+}
+
+// nonIdentifierTokenTypes are token types that don't name anything, so they
+// aren't tagged
+var nonIdentifierTokenTypes = []string{
+	string(protocol.KeywordType),
+	string(protocol.ModifierType),
+	string(protocol.CommentType),
+	string(protocol.StringType),
+	string(protocol.NumberType),
+	string(protocol.RegexpType),
+	string(protocol.OperatorType),
+}
+
+// ReadSemanticTokens returns lines of a file with each identifier followed by
+// its semantic token type and modifiers between « and ». If declarationsOnly
+// is set only the identifiers declared in the lines are listed. If startLine
+// is not positive the whole file is read.
+func ReadSemanticTokens(ctx context.Context, client *lsp.Client, filePath string, startLine, endLine int, declarationsOnly bool) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	// A final newline doesn't start another line
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if startLine <= 0 {
+		startLine, endLine = 1, len(lines)
+	}
+	if endLine < startLine {
+		endLine = startLine
+	}
+	if startLine > len(lines) {
+		return "", fmt.Errorf("start line %d is past the end of the file (%d lines)", startLine, len(lines))
+	}
+	endLine = min(endLine, len(lines))
+
+	tokens, err := semanticTokensIn(ctx, client, filePath, lines, startLine, endLine)
+	if err != nil {
+		return "", err
+	}
+
+	var identifiers []semanticToken
+	for _, token := range tokens {
+		if !slices.Contains(nonIdentifierTokenTypes, token.tokenType) {
+			identifiers = append(identifiers, token)
+		}
+	}
+
+	var result strings.Builder
+	if declarationsOnly {
+		declarations := listDeclarations(lines, identifiers)
+		fmt.Fprintf(&result, "%s, lines %d-%d, %d declarations:\n", filepath.Base(filePath), startLine, endLine, len(declarations))
+		for _, declaration := range declarations {
+			result.WriteString(declaration + "\n")
+		}
+		return result.String(), nil
+	}
+
+	fmt.Fprintf(&result, "%s, lines %d-%d, identifiers tagged with their kind and modifiers in «»:\n\n", filepath.Base(filePath), startLine, endLine)
+	tagged := tagLines(lines[startLine-1:endLine], startLine-1, identifiers)
+	result.WriteString(addLineNumbers(strings.Join(tagged, "\n"), startLine))
+	return result.String(), nil
+}
+
+// semanticTokensIn returns the decoded semantic tokens on lines startLine to
+// endLine (1-indexed) of a file, requesting only those lines if the server
+// supports it
+func semanticTokensIn(ctx context.Context, client *lsp.Client, filePath string, lines []string, startLine, endLine int) ([]semanticToken, error) {
+	legend, ok := client.SemanticTokensLegend()
+	if !ok {
+		return nil, fmt.Errorf("the language server does not provide semantic tokens")
+	}
+
+	uri := protocol.DocumentUri("file://" + filePath)
+	var result protocol.SemanticTokens
+	var err error
+	if client.SupportsSemanticTokensRange() {
+		result, err = client.SemanticTokensRange(ctx, protocol.SemanticTokensRangeParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Range: protocol.Range{
+				Start: protocol.Position{Line: uint32(startLine - 1)},
+				End:   protocol.Position{Line: uint32(endLine - 1), Character: utf16Offset(lines[endLine-1], len(lines[endLine-1]))},
+			},
+		})
+	} else {
+		result, err = client.SemanticTokensFull(ctx, protocol.SemanticTokensParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semantic tokens: %v", err)
+	}
+
+	var tokens []semanticToken
+	for _, token := range decodeSemanticTokens(result.Data, legend) {
+		if int(token.line) >= startLine-1 && int(token.line) < endLine {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+// decodeSemanticTokens decodes the relative encoding of semantic tokens:
+// groups of line delta, start delta, length, type index and modifier bits
+func decodeSemanticTokens(data []uint32, legend protocol.SemanticTokensLegend) []semanticToken {
+	var tokens []semanticToken
+	var line, start uint32
+	for i := 0; i+5 <= len(data); i += 5 {
+		deltaLine, deltaStart, length, typeIndex, modifierBits := data[i], data[i+1], data[i+2], data[i+3], data[i+4]
+		if deltaLine > 0 {
+			line += deltaLine
+			start = deltaStart
+		} else {
+			start += deltaStart
+		}
+
+		token := semanticToken{line: line, start: start, length: length}
+		if int(typeIndex) < len(legend.TokenTypes) {
+			token.tokenType = legend.TokenTypes[typeIndex]
+		}
+		for bit, modifier := range legend.TokenModifiers {
+			if bit < 32 && modifierBits&(1<<bit) != 0 {
+				token.modifiers = append(token.modifiers, modifier)
+			}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// tokenText returns the text a token covers, or "" if it is outside lines
+func tokenText(lines []string, token semanticToken) string {
+	if int(token.line) >= len(lines) {
+		return ""
+	}
+	line := lines[token.line]
+	return line[byteOffset(line, token.start):byteOffset(line, token.start+token.length)]
+}
+
+// tagLines writes each token's type and modifiers after it. lines start at
+// firstLine (0-indexed).
+func tagLines(lines []string, firstLine int, tokens []semanticToken) []string {
+	byLine := make(map[int][]semanticToken)
+	for _, token := range tokens {
+		byLine[int(token.line)-firstLine] = append(byLine[int(token.line)-firstLine], token)
+	}
+
+	tagged := make([]string, len(lines))
+	for i, line := range lines {
+		var text strings.Builder
+		written := 0
+		// Tokens are encoded in order, so they are already sorted
+		for _, token := range byLine[i] {
+			end := byteOffset(line, token.start+token.length)
+			if end < written {
+				continue
+			}
+			text.WriteString(line[written:end])
+			written = end
+
+			tags := append([]string{token.tokenType}, token.modifiers...)
+			text.WriteString("«" + strings.Join(tags, " ") + "»")
+		}
+		text.WriteString(line[written:])
+		tagged[i] = text.String()
+	}
+	return tagged
+}
+
+// listDeclarations describes the tokens that declare or define something,
+// e.g. "L12:6 function compute (deprecated)"
+func listDeclarations(lines []string, tokens []semanticToken) []string {
+	var declarations []string
+	for _, token := range tokens {
+		var others []string
+		declared := false
+		for _, modifier := range token.modifiers {
+			switch modifier {
+			case string(protocol.ModDeclaration), string(protocol.ModDefinition):
+				declared = true
+			default:
+				others = append(others, modifier)
+			}
+		}
+		if !declared {
+			continue
+		}
+
+		declaration := fmt.Sprintf("L%d:%d %s %s", token.line+1, token.start+1, token.tokenType, tokenText(lines, token))
+		if len(others) > 0 {
+			declaration += " (" + strings.Join(others, ", ") + ")"
+		}
+		declarations = append(declarations, declaration)
+	}
+	return declarations
+}
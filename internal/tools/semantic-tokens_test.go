@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeSemanticTokens(t *testing.T) {
+	legend := protocol.SemanticTokensLegend{
+		TokenTypes:     []string{"keyword", "function", "parameter", "type"},
+		TokenModifiers: []string{"definition", "readonly", "deprecated"},
+	}
+
+	// func compute(limit int)
+	// <next line>	old()
+	data := []uint32{
+		0, 0, 4, 0, 0,
+		0, 5, 7, 1, 1,
+		0, 8, 5, 2, 1,
+		0, 6, 3, 3, 0,
+		1, 1, 3, 1, 4,
+	}
+
+	assert.Equal(t, []semanticToken{
+		{line: 0, start: 0, length: 4, tokenType: "keyword"},
+		{line: 0, start: 5, length: 7, tokenType: "function", modifiers: []string{"definition"}},
+		{line: 0, start: 13, length: 5, tokenType: "parameter", modifiers: []string{"definition"}},
+		{line: 0, start: 19, length: 3, tokenType: "type"},
+		{line: 1, start: 1, length: 3, tokenType: "function", modifiers: []string{"deprecated"}},
+	}, decodeSemanticTokens(data, legend))
+}
+
+func TestTagLines(t *testing.T) {
+	lines := []string{"func compute(limit int) {", "\told()", "\ts := \"日本\" + größe"}
+	tokens := []semanticToken{
+		{line: 4, start: 5, length: 7, tokenType: "function", modifiers: []string{"definition"}},
+		{line: 4, start: 13, length: 5, tokenType: "parameter", modifiers: []string{"definition"}},
+		{line: 4, start: 19, length: 3, tokenType: "type", modifiers: []string{"defaultLibrary"}},
+		{line: 5, start: 1, length: 3, tokenType: "function", modifiers: []string{"deprecated"}},
+		// Positions count UTF-16 units, not bytes
+		{line: 6, start: 1, length: 1, tokenType: "variable"},
+		{line: 6, start: 13, length: 5, tokenType: "variable"},
+	}
+
+	assert.Equal(t, []string{
+		"func compute«function definition»(limit«parameter definition» int«type defaultLibrary») {",
+		"\told«function deprecated»()",
+		"\ts«variable» := \"日本\" + größe«variable»",
+	}, tagLines(lines, 4, tokens))
+}
+
+func TestListDeclarations(t *testing.T) {
+	lines := []string{"func compute(limit int) {", "\tconst max = 3", "\tπ, größe := 3, 4"}
+	tokens := []semanticToken{
+		{line: 0, start: 5, length: 7, tokenType: "function", modifiers: []string{"definition"}},
+		{line: 0, start: 13, length: 5, tokenType: "parameter", modifiers: []string{"definition"}},
+		{line: 0, start: 19, length: 3, tokenType: "type"},
+		{line: 1, start: 7, length: 3, tokenType: "variable", modifiers: []string{"declaration", "readonly"}},
+		{line: 2, start: 4, length: 5, tokenType: "variable", modifiers: []string{"definition"}},
+	}
+
+	assert.Equal(t, []string{
+		"L1:6 function compute",
+		"L1:14 parameter limit",
+		"L2:8 variable max (readonly)",
+		"L3:5 variable größe",
+	}, listDeclarations(lines, tokens))
+}
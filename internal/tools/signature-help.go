@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// GetSignatureHelp returns the signatures of the call enclosing a position,
+// with their documentation and the parameter being written
+func GetSignatureHelp(ctx context.Context, client *lsp.Client, filePath string, line, column int) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	help, err := client.SignatureHelp(ctx, protocol.SignatureHelpParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{
+				URI: protocol.DocumentUri("file://" + filePath),
+			},
+			Position: protocol.Position{
+				Line:      uint32(line - 1),
+				Character: uint32(column - 1),
+			},
+		},
+		Context: &protocol.SignatureHelpContext{
+			TriggerKind: protocol.SigInvoked,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get signature help: %v", err)
+	}
+
+	if len(help.Signatures) == 0 {
+		return fmt.Sprintf("No signature help at %s:%d:%d. The position must be inside the arguments of a call.", filepath.Base(filePath), line, column), nil
+	}
+	return formatSignatureHelp(help), nil
+}
+
+// formatSignatureHelp writes each signature with its documentation and
+// parameters, marking the active signature and parameter
+func formatSignatureHelp(help protocol.SignatureHelp) string {
+	var result strings.Builder
+	for i, sig := range help.Signatures {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+
+		active := uint32(i) == help.ActiveSignature
+		if len(help.Signatures) > 1 {
+			fmt.Fprintf(&result, "Signature %d of %d", i+1, len(help.Signatures))
+			if active {
+				result.WriteString(" (active)")
+			}
+			result.WriteString(":\n")
+		}
+		result.WriteString(sig.Label + "\n")
+
+		if doc := strings.TrimSpace(sig.Documentation.Text()); doc != "" {
+			result.WriteString("\n" + doc + "\n")
+		}
+
+		if len(sig.Parameters) == 0 {
+			continue
+		}
+
+		// A signature's own active parameter takes precedence over the one
+		// for the whole response
+		activeParam := help.ActiveParameter
+		if sig.ActiveParameter != nil {
+			activeParam = *sig.ActiveParameter
+		}
+
+		result.WriteString("\nParameters:\n")
+		for j, param := range sig.Parameters {
+			marker := "  "
+			if active && uint32(j) == activeParam {
+				marker = "> "
+			}
+			result.WriteString(marker + parameterLabel(sig.Label, param))
+			if doc := strings.TrimSpace(param.Documentation.Text()); doc != "" {
+				result.WriteString(" - " + strings.ReplaceAll(doc, "\n", " "))
+			}
+			result.WriteString("\n")
+		}
+	}
+	return result.String()
+}
+
+// parameterLabel returns a parameter's label, which is either a string or
+// UTF-16 offsets into the signature's label
+func parameterLabel(signature string, param protocol.ParameterInformation) string {
+	switch v := param.Label.Value.(type) {
+	case string:
+		return v
+	case protocol.Tuple_ParameterInformation_label_Item1:
+		if v.Fld0 <= v.Fld1 {
+			return signature[byteOffset(signature, v.Fld0):byteOffset(signature, v.Fld1)]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSignatureHelp(t *testing.T) {
+	param := func(label string, doc string) protocol.ParameterInformation {
+		p := protocol.ParameterInformation{Label: protocol.Or_ParameterInformation_label{Value: label}}
+		if doc != "" {
+			p.Documentation = &protocol.Or_ParameterInformation_documentation{Value: doc}
+		}
+		return p
+	}
+
+	t.Run("Single signature", func(t *testing.T) {
+		help := protocol.SignatureHelp{
+			Signatures: []protocol.SignatureInformation{{
+				Label: "Replace(s string, old string, new string, n int) string",
+				Documentation: &protocol.Or_SignatureInformation_documentation{Value: protocol.MarkupContent{
+					Kind:  protocol.Markdown,
+					Value: "Replace returns a copy of the string s.",
+				}},
+				Parameters: []protocol.ParameterInformation{
+					param("s string", ""),
+					param("old string", ""),
+					param("new string", ""),
+					param("n int", "number of replacements, or -1 for all"),
+				},
+			}},
+			ActiveParameter: 3,
+		}
+
+		assert.Equal(t, "Replace(s string, old string, new string, n int) string\n"+
+			"\n"+
+			"Replace returns a copy of the string s.\n"+
+			"\n"+
+			"Parameters:\n"+
+			"  s string\n"+
+			"  old string\n"+
+			"  new string\n"+
+			"> n int - number of replacements, or -1 for all\n", formatSignatureHelp(help))
+	})
+
+	t.Run("Overloads with label offsets", func(t *testing.T) {
+		help := protocol.SignatureHelp{
+			Signatures: []protocol.SignatureInformation{
+				{
+					Label:      "max(a: int, b: int) -> int",
+					Parameters: []protocol.ParameterInformation{{Label: protocol.Or_ParameterInformation_label{Value: protocol.Tuple_ParameterInformation_label_Item1{Fld0: 4, Fld1: 10}}}},
+				},
+				{
+					Label:      "max(values: list) -> int",
+					Parameters: []protocol.ParameterInformation{param("values: list", "")},
+				},
+			},
+			ActiveSignature: 1,
+		}
+
+		assert.Equal(t, "Signature 1 of 2:\n"+
+			"max(a: int, b: int) -> int\n"+
+			"\n"+
+			"Parameters:\n"+
+			"  a: int\n"+
+			"\n"+
+			"Signature 2 of 2 (active):\n"+
+			"max(values: list) -> int\n"+
+			"\n"+
+			"Parameters:\n"+
+			"> values: list\n", formatSignatureHelp(help))
+	})
+
+	t.Run("Signature sets the first parameter", func(t *testing.T) {
+		var help protocol.SignatureHelp
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"signatures": [{
+				"label": "größe(ä: int, b: int)",
+				"parameters": [{"label": [6, 12]}, {"label": [14, 20]}],
+				"activeParameter": 0
+			}],
+			"activeParameter": 1
+		}`), &help))
+
+		// Label offsets count UTF-16 units, not bytes
+		assert.Equal(t, "größe(ä: int, b: int)\n"+
+			"\n"+
+			"Parameters:\n"+
+			"> ä: int\n"+
+			"  b: int\n", formatSignatureHelp(help))
+	})
+}
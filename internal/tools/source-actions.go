@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// Source actions that apply to a whole file
+const (
+	SourceOrganizeImports = "organizeImports"
+	SourceFixAll          = "fixAll"
+)
+
+// sourceActionKinds maps source action names to code action kinds
+var sourceActionKinds = map[string]protocol.CodeActionKind{
+	SourceOrganizeImports: protocol.SourceOrganizeImports,
+	SourceFixAll:          protocol.SourceFixAll,
+}
+
+// ApplySourceAction runs a whole-file source action, such as organizing
+// imports, on a file and applies the result
+func ApplySourceAction(ctx context.Context, client *lsp.Client, filePath string, action string) (string, error) {
+	kind, ok := sourceActionKinds[action]
+	if !ok {
+		return "", fmt.Errorf("unknown source action %q, expected organizeImports or fixAll", action)
+	}
+
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %v", err)
+	}
+
+	uri := protocol.DocumentUri("file://" + filePath)
+	diagnostics := client.GetFileDiagnostics(uri)
+	if diagnostics == nil {
+		diagnostics = []protocol.Diagnostic{}
+	}
+
+	triggerKind := protocol.CodeActionInvoked
+	result, err := client.CodeAction(ctx, protocol.CodeActionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+		Range:        fileRange(string(content)),
+		Context: protocol.CodeActionContext{
+			Diagnostics: diagnostics,
+			Only:        []protocol.CodeActionKind{kind},
+			TriggerKind: &triggerKind,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get code actions: %v", err)
+	}
+
+	chosen, ok := chooseSourceAction(codeActionsFromResult(result), kind)
+	if !ok {
+		return fmt.Sprintf("No %s action available for %s", action, filePath), nil
+	}
+	return applyCodeAction(ctx, client, chosen)
+}
+
+// chooseSourceAction picks the action of a kind, or of a more specific kind
+// such as source.fixAll.eslint, preferring the one the server prefers. Only
+// one is chosen since each action's edits assume the file is unchanged.
+func chooseSourceAction(actions []protocol.CodeAction, kind protocol.CodeActionKind) (protocol.CodeAction, bool) {
+	var matching []protocol.CodeAction
+	for _, action := range actions {
+		if action.Disabled != nil {
+			continue
+		}
+		// Bare commands have no kind, but were only returned for this kind
+		if action.Kind == "" || action.Kind == kind || strings.HasPrefix(string(action.Kind), string(kind)+".") {
+			matching = append(matching, action)
+		}
+	}
+	for _, action := range matching {
+		if action.IsPreferred {
+			return action, true
+		}
+	}
+	if len(matching) > 0 {
+		return matching[0], true
+	}
+	return protocol.CodeAction{}, false
+}
+
+// fileRange returns a range covering all of a file's content
+func fileRange(content string) protocol.Range {
+	lines := strings.Split(content, "\n")
+	last := len(lines) - 1
+	return protocol.Range{
+		End: protocol.Position{Line: uint32(last), Character: utf16Offset(lines[last], len(lines[last]))},
+	}
+}
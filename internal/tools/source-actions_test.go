@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseSourceAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []protocol.CodeAction
+		want    string
+		found   bool
+	}{
+		{
+			name: "Exact kind",
+			actions: []protocol.CodeAction{
+				{Title: "Extract function", Kind: protocol.RefactorExtract},
+				{Title: "Organize Imports", Kind: protocol.SourceOrganizeImports},
+			},
+			want:  "Organize Imports",
+			found: true,
+		},
+		{
+			name: "More specific kind",
+			actions: []protocol.CodeAction{
+				{Title: "Organize imports (ruff)", Kind: "source.organizeImports.ruff"},
+			},
+			want:  "Organize imports (ruff)",
+			found: true,
+		},
+		{
+			name: "Preferred action wins",
+			actions: []protocol.CodeAction{
+				{Title: "Sort imports", Kind: protocol.SourceOrganizeImports},
+				{Title: "Organize Imports", Kind: protocol.SourceOrganizeImports, IsPreferred: true},
+			},
+			want:  "Organize Imports",
+			found: true,
+		},
+		{
+			name:    "Bare command",
+			actions: []protocol.CodeAction{{Title: "Organize Imports", Command: &protocol.Command{Command: "_typescript.organizeImports"}}},
+			want:    "Organize Imports",
+			found:   true,
+		},
+		{
+			name: "Disabled and unrelated actions are skipped",
+			actions: []protocol.CodeAction{
+				{Title: "Organize Imports", Kind: protocol.SourceOrganizeImports, Disabled: &protocol.CodeActionDisabled{Reason: "no imports"}},
+				{Title: "source.organizeImportsLater", Kind: "source.organizeImportsLater"},
+			},
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, found := chooseSourceAction(tt.actions, protocol.SourceOrganizeImports)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.want, action.Title)
+		})
+	}
+}
+
+func TestFileRange(t *testing.T) {
+	assert.Equal(t, protocol.Range{End: protocol.Position{Line: 3, Character: 0}}, fileRange("package main\n\nfunc main() {}\n"))
+	assert.Equal(t, protocol.Range{End: protocol.Position{Line: 1, Character: 4}}, fileRange("a\nbcde"))
+	assert.Equal(t, protocol.Range{}, fileRange(""))
+	// Characters count UTF-16 units, not bytes, and the emoji takes two
+	assert.Equal(t, protocol.Range{End: protocol.Position{Line: 1, Character: 4}}, fileRange("a\n日本😀"))
+}
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+const (
+	// DefaultTypeHierarchyDepth is how many levels of types are shown by default
+	DefaultTypeHierarchyDepth = 2
+
+	// maxTypeHierarchyDepth caps the requested depth
+	maxTypeHierarchyDepth = 10
+
+	// maxTypeHierarchyItems stops expanding the tree once this many types have
+	// been listed
+	maxTypeHierarchyItems = 200
+)
+
+// Type hierarchy directions
+const (
+	TypesSupertypes = "supertypes"
+	TypesSubtypes   = "subtypes"
+	TypesBoth       = "both"
+)
+
+// GetTypeHierarchy shows the supertypes and/or subtypes of the type at a
+// position, recursively up to depth levels
+func GetTypeHierarchy(ctx context.Context, client *lsp.Client, filePath string, line, column int, direction string, depth int) (string, error) {
+	if err := syncFile(ctx, client, filePath); err != nil {
+		return "", err
+	}
+
+	position := protocol.Position{
+		Line:      uint32(line - 1),
+		Character: uint32(column - 1),
+	}
+	uri := protocol.DocumentUri("file://" + filePath)
+
+	text, found, err := typeHierarchyAt(ctx, client, uri, position, direction, depth)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("No type found at %s:%d:%d", filePath, line, column) + indexingNotice(client), nil
+	}
+	return text, nil
+}
+
+// GetTypeHierarchyForSymbol shows the supertypes and/or subtypes of every
+// type with the given name
+func GetTypeHierarchyForSymbol(ctx context.Context, clients []*lsp.Client, symbolName string, direction string, depth int) (string, error) {
+	positions, err := findSymbolPositions(ctx, clients, symbolName)
+	if err != nil {
+		return "", err
+	}
+
+	var sections []string
+	for _, pos := range positions {
+		text, found, err := typeHierarchyAt(ctx, pos.client, pos.uri, pos.position, direction, depth)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			sections = append(sections, text)
+		}
+	}
+
+	if len(sections) == 0 {
+		return fmt.Sprintf("%s not found", symbolName) + indexingNotice(clients...), nil
+	}
+	return strings.Join(sections, "\n"), nil
+}
+
+// typeHierarchyAt prepares the type hierarchy at a position and renders it.
+// found is false if there is no type at the position.
+func typeHierarchyAt(ctx context.Context, client *lsp.Client, uri protocol.DocumentUri, position protocol.Position, direction string, depth int) (string, bool, error) {
+	directions, depth, err := hierarchyDirections(direction, TypesBoth, [2]string{TypesSupertypes, TypesSubtypes},
+		depth, DefaultTypeHierarchyDepth, maxTypeHierarchyDepth)
+	if err != nil {
+		return "", false, err
+	}
+
+	items, err := client.PrepareTypeHierarchy(ctx, protocol.TypeHierarchyPrepareParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     position,
+		},
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to prepare type hierarchy: %v", err)
+	}
+	if len(items) == 0 {
+		return "", false, nil
+	}
+
+	workspaceDir, err := os.Getwd()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get workspace directory: %v", err)
+	}
+
+	text, err := renderHierarchy(items, directions, func(dir string) *treeWriter[protocol.TypeHierarchyItem] {
+		return newTypeHierarchyWriter(dir, typeFetcher(ctx, client, dir), depth, workspaceDir)
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return text, true, nil
+}
+
+// newTypeHierarchyWriter returns a writer for a type tree in one direction.
+// related fetches the supertypes or subtypes of an item.
+func newTypeHierarchyWriter(direction string, related func(protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error), maxDepth int, workspaceDir string) *treeWriter[protocol.TypeHierarchyItem] {
+	header := "Supertypes of "
+	if direction == TypesSubtypes {
+		header = "Subtypes of "
+	}
+	return &treeWriter[protocol.TypeHierarchyItem]{
+		header:       header,
+		related:      related,
+		item:         typeHierarchyItem,
+		cycleNote:    " (cycle)",
+		expandedNote: fmt.Sprintf(" (%s listed above)", direction),
+		noun:         "types",
+		maxItems:     maxTypeHierarchyItems,
+		maxDepth:     maxDepth,
+		workspaceDir: workspaceDir,
+	}
+}
+
+// typeFetcher returns a function that asks the server for the supertypes or
+// subtypes of an item
+func typeFetcher(ctx context.Context, client *lsp.Client, direction string) func(protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+	if direction == TypesSupertypes {
+		return func(item protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+			items, err := client.Supertypes(ctx, protocol.TypeHierarchySupertypesParams{Item: item})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get supertypes: %v", err)
+			}
+			return items, nil
+		}
+	}
+	return func(item protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+		items, err := client.Subtypes(ctx, protocol.TypeHierarchySubtypesParams{Item: item})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subtypes: %v", err)
+		}
+		return items, nil
+	}
+}
+
+// typeHierarchyItem returns the fields of a type hierarchy item shown in the tree
+func typeHierarchyItem(item protocol.TypeHierarchyItem) hierarchyItem {
+	return hierarchyItem{name: item.Name, kind: item.Kind, detail: item.Detail, uri: item.URI, selection: item.SelectionRange}
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeHierarchyWriter(t *testing.T) {
+	item := func(name string, kind protocol.SymbolKind, line uint32) protocol.TypeHierarchyItem {
+		return protocol.TypeHierarchyItem{
+			Name:           name,
+			Kind:           kind,
+			URI:            "file:///src/shapes.ts",
+			SelectionRange: lineRange(line, line),
+		}
+	}
+	shape := item("Shape", protocol.Interface, 0)
+	named := item("Named", protocol.Interface, 5)
+	square := item("Square", protocol.Class, 10)
+	rect := item("Rect", protocol.Class, 20)
+
+	// Square extends Rect, which implements Shape and Named. Shape is also
+	// reported as extending Square to check cycles are cut.
+	supertypes := map[string][]protocol.TypeHierarchyItem{
+		"Square": {rect},
+		"Rect":   {shape, named},
+		"Shape":  {square},
+	}
+	subtypes := map[string][]protocol.TypeHierarchyItem{
+		"Shape": {rect},
+		"Named": {rect},
+		"Rect":  {square},
+	}
+	fetch := func(types map[string][]protocol.TypeHierarchyItem) func(protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+		return func(item protocol.TypeHierarchyItem) ([]protocol.TypeHierarchyItem, error) {
+			return types[item.Name], nil
+		}
+	}
+
+	t.Run("Supertypes with cycle", func(t *testing.T) {
+		w := newTypeHierarchyWriter(TypesSupertypes, fetch(supertypes), 5, "/src")
+		require.NoError(t, w.write(square))
+		assert.Equal(t, "Supertypes of Class Square - shapes.ts:11\n"+
+			"  Class Rect - shapes.ts:21\n"+
+			"    Interface Shape - shapes.ts:1\n"+
+			"      Class Square - shapes.ts:11 (cycle)\n"+
+			"    Interface Named - shapes.ts:6\n", w.output.String())
+	})
+
+	t.Run("Depth limit", func(t *testing.T) {
+		w := newTypeHierarchyWriter(TypesSupertypes, fetch(supertypes), 1, "/src")
+		require.NoError(t, w.write(square))
+		assert.Equal(t, "Supertypes of Class Square - shapes.ts:11\n"+
+			"  Class Rect - shapes.ts:21\n", w.output.String())
+	})
+
+	t.Run("Subtypes", func(t *testing.T) {
+		w := newTypeHierarchyWriter(TypesSubtypes, fetch(subtypes), 5, "/src")
+		require.NoError(t, w.write(shape))
+		assert.Equal(t, "Subtypes of Interface Shape - shapes.ts:1\n"+
+			"  Class Rect - shapes.ts:21\n"+
+			"    Class Square - shapes.ts:11\n", w.output.String())
+	})
+
+	t.Run("No subtypes", func(t *testing.T) {
+		w := newTypeHierarchyWriter(TypesSubtypes, fetch(subtypes), 5, "/src")
+		require.NoError(t, w.write(square))
+		assert.Equal(t, "Subtypes of Class Square - shapes.ts:11\n  (none)\n", w.output.String())
+	})
+}
@@ -7,9 +7,21 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
 	"github.com/isaacphi/mcp-language-server/internal/protocol"
 )
 
+// hasQualifiedSuffix reports whether name is memberName qualified by a
+// container using one of the server's separators, e.g. Type.Method
+func hasQualifiedSuffix(profile *lsp.Profile, name, memberName string) bool {
+	for _, sep := range profile.SymbolSeparators {
+		if strings.HasSuffix(name, sep+memberName) {
+			return true
+		}
+	}
+	return false
+}
+
 func ExtractTextFromLocation(loc protocol.Location) (string, error) {
 	path := strings.TrimPrefix(string(loc.URI), "file://")
 
@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/isaacphi/mcp-language-server/internal/utilities"
+)
+
+// workspaceDiagnosticsTimeout bounds the workspace/diagnostic request. Servers
+// may hold the request open until something changes, so whatever has been
+// cached by then is used.
+const workspaceDiagnosticsTimeout = 5 * time.Second
+
+// DefaultMaxWorkspaceDiagnostics is the default limit on the number of
+// diagnostics included in the workspace diagnostics output
+const DefaultMaxWorkspaceDiagnostics = 100
+
+// WorkspaceDiagnosticsFilter selects which diagnostics are reported
+type WorkspaceDiagnosticsFilter struct {
+	// MinSeverity is the least severe diagnostic to include, e.g.
+	// SeverityWarning includes errors and warnings. Zero includes everything.
+	MinSeverity protocol.DiagnosticSeverity
+
+	// PathGlob limits diagnostics to files matching the glob, relative to the
+	// workspace or absolute. "**" matches any number of directories.
+	PathGlob string
+
+	// Source limits diagnostics to those reported by a source, e.g. "compiler"
+	Source string
+}
+
+// GetWorkspaceDiagnostics reports the diagnostics the language servers know
+// about across the workspace, grouped by file and limited to maxDiagnostics
+func GetWorkspaceDiagnostics(ctx context.Context, clients []*lsp.Client, filter WorkspaceDiagnosticsFilter, maxDiagnostics int) (string, error) {
+	all := make(map[protocol.DocumentUri][]protocol.Diagnostic)
+	for _, client := range clients {
+		if client.SupportsWorkspaceDiagnostics() {
+			pullCtx, cancel := context.WithTimeout(ctx, workspaceDiagnosticsTimeout)
+			_, err := client.PullWorkspaceDiagnostics(pullCtx)
+			cancel()
+			if err != nil {
+				if ctx.Err() != nil {
+					return "", ctx.Err()
+				}
+				if !errors.Is(err, lsp.ErrRequestTimeout) && !errors.Is(err, context.DeadlineExceeded) {
+					toolsLogger.Warn("Failed to pull workspace diagnostics: %v", err)
+				}
+			}
+		}
+
+		for uri, diagnostics := range client.GetAllDiagnostics() {
+			all[uri] = append(all[uri], diagnostics...)
+		}
+	}
+
+	workspaceDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get workspace directory: %v", err)
+	}
+
+	return formatWorkspaceDiagnostics(all, workspaceDir, filter, maxDiagnostics), nil
+}
+
+// formatWorkspaceDiagnostics filters and formats diagnostics grouped by file.
+// Files with the most errors are listed first.
+func formatWorkspaceDiagnostics(all map[protocol.DocumentUri][]protocol.Diagnostic, workspaceDir string, filter WorkspaceDiagnosticsFilter, maxDiagnostics int) string {
+	type fileResult struct {
+		path        string
+		diagnostics []protocol.Diagnostic
+		errors      int
+	}
+
+	var files []fileResult
+	total := 0
+	for uri, diagnostics := range all {
+		path := strings.TrimPrefix(string(uri), "file://")
+		if filter.PathGlob != "" && !utilities.MatchPathGlob(filter.PathGlob, path, workspaceDir) {
+			continue
+		}
+
+		result := fileResult{path: path}
+		for _, diag := range diagnostics {
+			if !filter.matches(diag) {
+				continue
+			}
+			if effectiveSeverity(diag) == protocol.SeverityError {
+				result.errors++
+			}
+			result.diagnostics = append(result.diagnostics, diag)
+		}
+		if len(result.diagnostics) == 0 {
+			continue
+		}
+
+		sort.SliceStable(result.diagnostics, func(i, j int) bool {
+			a, b := result.diagnostics[i].Range.Start, result.diagnostics[j].Range.Start
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Character < b.Character
+		})
+		files = append(files, result)
+		total += len(result.diagnostics)
+	}
+
+	if total == 0 {
+		return "No diagnostics found in workspace"
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].errors != files[j].errors {
+			return files[i].errors > files[j].errors
+		}
+		return files[i].path < files[j].path
+	})
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Diagnostics in Workspace: %d in %d files\n", total, len(files)))
+
+	shown := 0
+	listedFiles := 0
+	for _, file := range files {
+		if maxDiagnostics > 0 && shown >= maxDiagnostics {
+			break
+		}
+		listedFiles++
+
+		displayPath := file.path
+		if rel, err := filepath.Rel(workspaceDir, file.path); err == nil && !strings.HasPrefix(rel, "..") {
+			displayPath = rel
+		}
+		output.WriteString(fmt.Sprintf("\n%s (%d)\n", displayPath, len(file.diagnostics)))
+
+		for _, diag := range file.diagnostics {
+			if maxDiagnostics > 0 && shown >= maxDiagnostics {
+				break
+			}
+			shown++
+			output.WriteString(fmt.Sprintf("  %s at L%d:C%d: %s",
+				getSeverityString(effectiveSeverity(diag)),
+				diag.Range.Start.Line+1,
+				diag.Range.Start.Character+1,
+				strings.TrimSpace(diag.Message)))
+			if diag.Source != "" {
+				output.WriteString(fmt.Sprintf(" (Source: %s)", diag.Source))
+			}
+			output.WriteString("\n")
+		}
+	}
+
+	if shown < total {
+		output.WriteString(fmt.Sprintf("\n%d more diagnostics not shown", total-shown))
+		if hiddenFiles := len(files) - listedFiles; hiddenFiles > 0 {
+			output.WriteString(fmt.Sprintf(", including all of %d files", hiddenFiles))
+		}
+		output.WriteString(". Narrow the results with severity, path or source filters.\n")
+	}
+
+	return output.String()
+}
+
+// effectiveSeverity returns a diagnostic's severity. Diagnostics without one
+// are treated as errors, as the spec suggests clients interpret them.
+func effectiveSeverity(diag protocol.Diagnostic) protocol.DiagnosticSeverity {
+	if diag.Severity == 0 {
+		return protocol.SeverityError
+	}
+	return diag.Severity
+}
+
+// matches reports whether a diagnostic passes the severity and source filters
+func (f WorkspaceDiagnosticsFilter) matches(diag protocol.Diagnostic) bool {
+	if f.MinSeverity != 0 && effectiveSeverity(diag) > f.MinSeverity {
+		return false
+	}
+	if f.Source != "" && !strings.EqualFold(diag.Source, f.Source) {
+		return false
+	}
+	return true
+}
+
+// ParseSeverity converts a severity name such as "error" or "warning" to a
+// diagnostic severity
+func ParseSeverity(name string) (protocol.DiagnosticSeverity, error) {
+	switch strings.ToLower(name) {
+	case "", "all":
+		return 0, nil
+	case "error", "errors":
+		return protocol.SeverityError, nil
+	case "warning", "warnings":
+		return protocol.SeverityWarning, nil
+	case "info", "information":
+		return protocol.SeverityInformation, nil
+	case "hint", "hints":
+		return protocol.SeverityHint, nil
+	default:
+		return 0, fmt.Errorf("unknown severity: %s", name)
+	}
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func diagnosticAt(line uint32, severity protocol.DiagnosticSeverity, source, message string) protocol.Diagnostic {
+	return protocol.Diagnostic{
+		Range: protocol.Range{
+			Start: protocol.Position{Line: line, Character: 0},
+			End:   protocol.Position{Line: line, Character: 1},
+		},
+		Severity: severity,
+		Source:   source,
+		Message:  message,
+	}
+}
+
+func TestFormatWorkspaceDiagnostics(t *testing.T) {
+	all := map[protocol.DocumentUri][]protocol.Diagnostic{
+		"file:///ws/a.go": {
+			diagnosticAt(9, protocol.SeverityWarning, "vet", "unused result"),
+		},
+		"file:///ws/b.go": {
+			diagnosticAt(4, protocol.SeverityError, "compiler", "undefined: foo"),
+			diagnosticAt(1, protocol.SeverityError, "compiler", "missing return"),
+			diagnosticAt(2, protocol.SeverityHint, "compiler", "could be simplified"),
+		},
+		"file:///ws/clean.go": {},
+	}
+
+	t.Run("Errors first and sorted by line", func(t *testing.T) {
+		result := formatWorkspaceDiagnostics(all, "/ws", WorkspaceDiagnosticsFilter{MinSeverity: protocol.SeverityWarning}, 0)
+		assert.Equal(t, "Diagnostics in Workspace: 3 in 2 files\n"+
+			"\nb.go (2)\n"+
+			"  ERROR at L2:C1: missing return (Source: compiler)\n"+
+			"  ERROR at L5:C1: undefined: foo (Source: compiler)\n"+
+			"\na.go (1)\n"+
+			"  WARNING at L10:C1: unused result (Source: vet)\n", result)
+	})
+
+	t.Run("Source filter", func(t *testing.T) {
+		result := formatWorkspaceDiagnostics(all, "/ws", WorkspaceDiagnosticsFilter{Source: "vet"}, 0)
+		assert.Contains(t, result, "1 in 1 files")
+		assert.NotContains(t, result, "b.go")
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		result := formatWorkspaceDiagnostics(all, "/ws", WorkspaceDiagnosticsFilter{}, 2)
+		assert.Contains(t, result, "could be simplified")
+		assert.NotContains(t, result, "undefined: foo")
+		// b.go is partly shown, so only a.go is omitted
+		assert.Contains(t, result, "2 more diagnostics not shown, including all of 1 files.")
+	})
+
+	t.Run("Limit within the last file", func(t *testing.T) {
+		result := formatWorkspaceDiagnostics(all, "/ws", WorkspaceDiagnosticsFilter{MinSeverity: protocol.SeverityError}, 1)
+		assert.Contains(t, result, "1 more diagnostics not shown. Narrow")
+	})
+
+	t.Run("Unset severity counts as an error", func(t *testing.T) {
+		unset := map[protocol.DocumentUri][]protocol.Diagnostic{
+			"file:///ws/a.go": {
+				diagnosticAt(0, protocol.SeverityError, "compiler", "missing return"),
+			},
+			"file:///ws/b.go": {
+				diagnosticAt(3, 0, "linter", "line too long"),
+				diagnosticAt(7, 0, "linter", "exported without comment"),
+			},
+		}
+		result := formatWorkspaceDiagnostics(unset, "/ws", WorkspaceDiagnosticsFilter{MinSeverity: protocol.SeverityError}, 0)
+		assert.Equal(t, "Diagnostics in Workspace: 3 in 2 files\n"+
+			"\nb.go (2)\n"+
+			"  ERROR at L4:C1: line too long (Source: linter)\n"+
+			"  ERROR at L8:C1: exported without comment (Source: linter)\n"+
+			"\na.go (1)\n"+
+			"  ERROR at L1:C1: missing return (Source: compiler)\n", result)
+	})
+
+	t.Run("Nothing matches", func(t *testing.T) {
+		result := formatWorkspaceDiagnostics(all, "/ws", WorkspaceDiagnosticsFilter{PathGlob: "*.py"}, 0)
+		assert.Equal(t, "No diagnostics found in workspace", result)
+	})
+}
@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/isaacphi/mcp-language-server/internal/lsp"
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+)
+
+// DefaultWorkspaceSymbolsLimit is the default number of symbols returned per
+// page of workspace symbol results
+const DefaultWorkspaceSymbolsLimit = 50
+
+// Match quality of a symbol name against the query, best first
+const (
+	matchNone = iota
+	matchFuzzy
+	matchSubstring
+	matchPrefix
+	matchExactFold
+	matchExact
+)
+
+// symbolMatch is a workspace symbol found by one of the servers
+type symbolMatch struct {
+	client    *lsp.Client
+	symbol    protocol.WorkspaceSymbolResult
+	name      string
+	kind      protocol.SymbolKind
+	container string
+	location  protocol.Location
+	quality   int
+	score     float64
+}
+
+// SearchWorkspaceSymbols finds symbols whose names match a query, ranked by
+// how closely they match. Results are paged with offset and limit and can be
+// limited to symbol kinds such as "function" or "class".
+func SearchWorkspaceSymbols(ctx context.Context, clients []*lsp.Client, query string, kinds []string, offset, limit int) (string, error) {
+	kindFilter, err := parseSymbolKinds(kinds)
+	if err != nil {
+		return "", err
+	}
+	if limit <= 0 {
+		limit = DefaultWorkspaceSymbolsLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	matches, err := fromEachClient(clients, "Workspace symbol search", func(client *lsp.Client) ([]symbolMatch, error) {
+		return searchSymbols(ctx, client, query, kindFilter)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	matches = dedupeSymbolMatches(matches)
+	rankSymbolMatches(matches)
+
+	if len(matches) == 0 {
+		return fmt.Sprintf("No symbols found matching %q", query) + indexingNotice(clients...), nil
+	}
+	if offset >= len(matches) {
+		return fmt.Sprintf("Found %d symbols matching %q, none after offset %d", len(matches), query, offset), nil
+	}
+
+	end := min(offset+limit, len(matches))
+	page := matches[offset:end]
+
+	// Only resolve the locations that will be shown
+	for i := range page {
+		resolveSymbolLocation(ctx, &page[i])
+	}
+
+	workspaceDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get workspace directory: %v", err)
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Found %d symbols matching %q (showing %d-%d):\n", len(matches), query, offset+1, end))
+	for _, match := range page {
+		output.WriteString(formatSymbolMatch(match, workspaceDir))
+		output.WriteString("\n")
+	}
+	if end < len(matches) {
+		output.WriteString(fmt.Sprintf("\n%d more symbols. Use offset=%d to see more.\n", len(matches)-end, end))
+	}
+
+	return output.String(), nil
+}
+
+// searchSymbols returns the symbols one server finds for a query that match
+// it and pass the kind filter
+func searchSymbols(ctx context.Context, client *lsp.Client, query string, kindFilter map[protocol.SymbolKind]bool) ([]symbolMatch, error) {
+	symbolResult, err := client.Symbol(ctx, protocol.WorkspaceSymbolParams{
+		Query: query,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch symbols: %v", err)
+	}
+
+	results, err := symbolResult.Results()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse results: %v", err)
+	}
+
+	var matches []symbolMatch
+	for _, symbol := range results {
+		match := newSymbolMatch(client, symbol, query)
+		// Servers match loosely, e.g. on containers or with typos
+		if match.quality == matchNone {
+			continue
+		}
+		if len(kindFilter) > 0 && !kindFilter[match.kind] {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+func newSymbolMatch(client *lsp.Client, symbol protocol.WorkspaceSymbolResult, query string) symbolMatch {
+	match := symbolMatch{
+		client:   client,
+		symbol:   symbol,
+		name:     symbol.GetName(),
+		location: symbol.GetLocation(),
+	}
+	switch v := symbol.(type) {
+	case *protocol.SymbolInformation:
+		match.kind = v.Kind
+		match.container = v.ContainerName
+	case *protocol.WorkspaceSymbol:
+		match.kind = v.Kind
+		match.container = v.ContainerName
+		match.score = v.Score
+	}
+	match.quality = matchQuality(query, match.name)
+	return match
+}
+
+// matchQuality rates how well a symbol name matches a query. Names are also
+// compared without their container, e.g. Start in (*Server).Start.
+func matchQuality(query, name string) int {
+	quality := nameMatchQuality(query, name)
+	if i := strings.LastIndexAny(name, ".:"); i >= 0 && i < len(name)-1 {
+		quality = max(quality, nameMatchQuality(query, name[i+1:]))
+	}
+	return quality
+}
+
+func nameMatchQuality(query, name string) int {
+	lowerQuery, lowerName := strings.ToLower(query), strings.ToLower(name)
+	switch {
+	case name == query:
+		return matchExact
+	case lowerName == lowerQuery:
+		return matchExactFold
+	case strings.HasPrefix(lowerName, lowerQuery):
+		return matchPrefix
+	case strings.Contains(lowerName, lowerQuery):
+		return matchSubstring
+	case isSubsequence(lowerQuery, lowerName):
+		return matchFuzzy
+	}
+	return matchNone
+}
+
+// isSubsequence reports whether the characters of query appear in name in order
+func isSubsequence(query, name string) bool {
+	remaining := []rune(query)
+	for _, r := range name {
+		if len(remaining) > 0 && remaining[0] == r {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// rankSymbolMatches orders matches by match quality, then by the server's
+// score if it gives one, then by shorter names
+func rankSymbolMatches(matches []symbolMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.quality != b.quality {
+			return a.quality > b.quality
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if len(a.name) != len(b.name) {
+			return len(a.name) < len(b.name)
+		}
+		return a.location.URI < b.location.URI
+	})
+}
+
+// dedupeSymbolMatches drops symbols reported more than once, which happens
+// when several servers index the same files
+func dedupeSymbolMatches(matches []symbolMatch) []symbolMatch {
+	seen := make(map[string]bool)
+	deduped := matches[:0]
+	for _, match := range matches {
+		key := fmt.Sprintf("%s|%d|%s|%d:%d", match.name, match.kind, match.location.URI,
+			match.location.Range.Start.Line, match.location.Range.Start.Character)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, match)
+	}
+	return deduped
+}
+
+// resolveSymbolLocation asks the server for the range of a symbol it only
+// reported the file for
+func resolveSymbolLocation(ctx context.Context, match *symbolMatch) {
+	ws, ok := match.symbol.(*protocol.WorkspaceSymbol)
+	if !ok {
+		return
+	}
+	_, uriOnly := ws.Location.Value.(protocol.LocationUriOnly)
+	// A location without a range also decodes as a Location with an empty range
+	if !uriOnly && (ws.Data == nil || match.location.Range != (protocol.Range{})) {
+		return
+	}
+
+	resolved, err := match.client.ResolveWorkspaceSymbol(ctx, *ws)
+	if err != nil {
+		toolsLogger.Debug("Failed to resolve workspace symbol %s: %v", match.name, err)
+		return
+	}
+	if loc, ok := resolved.Location.Value.(protocol.Location); ok {
+		match.location = loc
+	}
+}
+
+func formatSymbolMatch(match symbolMatch, workspaceDir string) string {
+	path := displayPath(match.location.URI, workspaceDir)
+
+	text := fmt.Sprintf("%s %s", protocol.TableKindMap[match.kind], match.name)
+	if match.container != "" {
+		text += fmt.Sprintf(" (in %s)", match.container)
+	}
+	// Locations the server could not resolve only have a file
+	if match.location.Range == (protocol.Range{}) {
+		return fmt.Sprintf("%s - %s", text, path)
+	}
+	return fmt.Sprintf("%s - %s:%d", text, path, match.location.Range.Start.Line+1)
+}
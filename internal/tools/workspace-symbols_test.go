@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/isaacphi/mcp-language-server/internal/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchQuality(t *testing.T) {
+	testCases := []struct {
+		query    string
+		name     string
+		expected int
+	}{
+		{"Server", "Server", matchExact},
+		{"server", "Server", matchExactFold},
+		{"Start", "(*Server).Start", matchExact},
+		{"push", "Vec::push", matchExact},
+		{"Serv", "ServerConfig", matchPrefix},
+		{"Config", "ServerConfig", matchSubstring},
+		{"srvcfg", "ServerConfig", matchFuzzy},
+		{"größe", "GrößeBerechnen", matchPrefix},
+		{"grbr", "GrößeBerechnen", matchFuzzy},
+		{"ößb", "GrößeBerechnen", matchFuzzy},
+		{"öß", "Grosse", matchNone},
+		{"xyz", "ServerConfig", matchNone},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query+"/"+tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, matchQuality(tc.query, tc.name))
+		})
+	}
+}
+
+func TestRankSymbolMatches(t *testing.T) {
+	symbol := func(name string, score float64) symbolMatch {
+		return symbolMatch{
+			name:     name,
+			quality:  matchQuality("Server", name),
+			score:    score,
+			location: protocol.Location{URI: protocol.DocumentUri("file:///ws/" + name + ".go")},
+		}
+	}
+	matches := []symbolMatch{
+		symbol("NewServerWithConfig", 0),
+		symbol("ServerConfig", 0.2),
+		symbol("Server", 0),
+		symbol("ServerOption", 0.9),
+		symbol("NewServer", 0),
+	}
+
+	rankSymbolMatches(matches)
+
+	var names []string
+	for _, match := range matches {
+		names = append(names, match.name)
+	}
+	assert.Equal(t, []string{"Server", "ServerOption", "ServerConfig", "NewServer", "NewServerWithConfig"}, names)
+}
+
+func TestDedupeSymbolMatches(t *testing.T) {
+	location := protocol.Location{URI: "file:///ws/a.go", Range: lineRange(3, 5)}
+	matches := []symbolMatch{
+		{name: "Run", kind: protocol.Function, location: location},
+		{name: "Run", kind: protocol.Function, location: location},
+		{name: "Run", kind: protocol.Method, location: location},
+	}
+	assert.Len(t, dedupeSymbolMatches(matches), 2)
+}
+
+func TestFormatSymbolMatch(t *testing.T) {
+	match := symbolMatch{
+		name:      "Start",
+		kind:      protocol.Method,
+		container: "Server",
+		location:  protocol.Location{URI: "file:///ws/server/server.go", Range: lineRange(41, 50)},
+	}
+	assert.Equal(t, "Method Start (in Server) - server/server.go:42", formatSymbolMatch(match, "/ws"))
+
+	// Unresolved locations only have a file
+	match.location.Range = protocol.Range{}
+	match.container = ""
+	assert.Equal(t, "Method Start - server/server.go", formatSymbolMatch(match, "/ws"))
+}
@@ -0,0 +1,58 @@
+package utilities
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// MatchGlob reports whether a slash separated path matches a glob pattern.
+// A "**" segment matches any number of directories, and other segments use
+// filepath.Match syntax.
+func MatchGlob(pattern, path string) bool {
+	return matchGlobSegments(
+		strings.Split(filepath.ToSlash(pattern), "/"),
+		strings.Split(filepath.ToSlash(path), "/"),
+	)
+}
+
+// matchGlobSegments matches path segments against glob segments, where a
+// "**" segment matches zero or more path segments
+func matchGlobSegments(glob, path []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if matchGlobSegments(glob[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if matched, err := filepath.Match(glob[0], path[0]); err != nil || !matched {
+			return false
+		}
+		glob, path = glob[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+// MatchPathGlob reports whether a file path matches a glob. Relative globs are
+// matched against the path relative to baseDir, and globs without a
+// directory, like "*.go", against the file name.
+func MatchPathGlob(pattern, path, baseDir string) bool {
+	pattern = filepath.ToSlash(pattern)
+	if !strings.Contains(pattern, "/") {
+		matched, err := filepath.Match(pattern, filepath.Base(path))
+		return err == nil && matched
+	}
+	if !filepath.IsAbs(pattern) {
+		rel, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return false
+		}
+		path = rel
+	}
+	return MatchGlob(pattern, path)
+}
@@ -0,0 +1,32 @@
+package utilities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	testCases := []struct {
+		glob     string
+		path     string
+		expected bool
+	}{
+		{"*.go", "/ws/main.go", true},
+		{"*.go", "/ws/pkg/util.go", true},
+		{"*.go", "/ws/main.ts", false},
+		{"src/*.ts", "/ws/src/index.ts", true},
+		{"src/*.ts", "/ws/src/lib/index.ts", false},
+		{"src/**/*.ts", "/ws/src/index.ts", true},
+		{"src/**/*.ts", "/ws/src/lib/deep/index.ts", true},
+		{"**/test/*", "/ws/a/b/test/x.py", true},
+		{"/ws/pkg/*.go", "/ws/pkg/util.go", true},
+		{"/other/*.go", "/ws/main.go", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.glob+" "+tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MatchPathGlob(tc.glob, tc.path, "/ws"))
+		})
+	}
+}
@@ -28,8 +28,9 @@ type WorkspaceWatcher struct {
 	debounceMu  sync.Mutex
 
 	// File watchers registered by the server
-	registrations  []protocol.FileSystemWatcher
-	registrationMu sync.RWMutex
+	registrations   []protocol.FileSystemWatcher
+	registrationIDs map[string]bool
+	registrationMu  sync.RWMutex
 
 	// Gitignore matcher
 	gitignore *GitignoreMatcher
@@ -43,10 +44,11 @@ func NewWorkspaceWatcher(client LSPClient) *WorkspaceWatcher {
 // NewWorkspaceWatcherWithConfig creates a new workspace watcher with custom configuration
 func NewWorkspaceWatcherWithConfig(client LSPClient, config *WatcherConfig) *WorkspaceWatcher {
 	return &WorkspaceWatcher{
-		client:        client,
-		config:        config,
-		debounceMap:   make(map[string]*time.Timer),
-		registrations: []protocol.FileSystemWatcher{},
+		client:          client,
+		config:          config,
+		debounceMap:     make(map[string]*time.Timer),
+		registrations:   []protocol.FileSystemWatcher{},
+		registrationIDs: make(map[string]bool),
 	}
 }
 
@@ -55,12 +57,19 @@ func (w *WorkspaceWatcher) AddRegistrations(ctx context.Context, id string, watc
 	w.registrationMu.Lock()
 	defer w.registrationMu.Unlock()
 
-	// Add new watchers
-	w.registrations = append(w.registrations, watchers...)
+	// Registrations are replayed after a language server restart. Don't add
+	// them twice, but still rescan so the new server sees the matching files.
+	if w.registrationIDs[id] {
+		watcherLogger.Info("File watcher registration %s already known, rescanning workspace", id)
+	} else {
+		// Add new watchers
+		w.registrations = append(w.registrations, watchers...)
+		w.registrationIDs[id] = true
 
-	// Log registration information
-	watcherLogger.Info("Added %d file watcher registrations (id: %s), total: %d",
-		len(watchers), id, len(w.registrations))
+		// Log registration information
+		watcherLogger.Info("Added %d file watcher registrations (id: %s), total: %d",
+			len(watchers), id, len(w.registrations))
+	}
 
 	// Detailed debug information about registrations
 	if watcherLogger.IsLevelEnabled(logging.LevelDebug) {
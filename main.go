@@ -2,15 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	configfile "github.com/isaacphi/mcp-language-server/internal/config"
 	"github.com/isaacphi/mcp-language-server/internal/logging"
 	"github.com/isaacphi/mcp-language-server/internal/lsp"
 	"github.com/isaacphi/mcp-language-server/internal/watcher"
@@ -21,29 +25,90 @@ import (
 var coreLogger = logging.NewLogger(logging.Core)
 
 type config struct {
-	workspaceDir string
-	lspCommand   string
-	lspArgs      []string
+	workspaceDir   string
+	configPath     string
+	settingsPath   string
+	settings       *lsp.Settings
+	lspCommand     string
+	lspArgs        []string
+	lspProfile     string
+	servers        []serverConfig
+	requestTimeout time.Duration
+	transport      string
+	listen         string
+	authToken      string
+	watcher        configfile.WatcherConfig
+	tools          configfile.ToolsConfig
+
+	// Format files after edit_file changes them
+	formatAfterEdit bool
+}
+
+// serverConfig describes a language server and the files it handles
+type serverConfig struct {
+	name      string
+	command   string
+	args      []string
+	languages []string
+	globs     []string
+	profile   string
+	client    *lsp.ClientConfig
+}
+
+// serverFlags collects repeated --server flags
+type serverFlags []string
+
+func (f *serverFlags) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *serverFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
 }
 
 type mcpServer struct {
 	config           config
-	lspClient        *lsp.Client
+	router           *lsp.Router
 	mcpServer        *server.MCPServer
 	ctx              context.Context
 	cancelFunc       context.CancelFunc
 	workspaceWatcher *watcher.WorkspaceWatcher
+	toolNames        []string
+	httpServer       *server.SSEServer
+	httpMu           sync.Mutex
 }
 
 func parseConfig() (*config, error) {
 	cfg := &config{}
+	var servers serverFlags
 	flag.StringVar(&cfg.workspaceDir, "workspace", "", "Path to workspace directory")
+	flag.StringVar(&cfg.configPath, "config", "", "Path to a configuration file (default: "+configfile.DefaultFileName+" in the workspace, if present)")
+	flag.StringVar(&cfg.settingsPath, "settings", "", "Path to a JSON file of settings returned to the language servers' workspace/configuration requests. Reloaded when it changes.")
 	flag.StringVar(&cfg.lspCommand, "lsp", "", "LSP command to run (args should be passed after --)")
+	flag.StringVar(&cfg.lspProfile, "profile", "", "Profile for the --lsp server, one of: "+strings.Join(lsp.Profiles(), ", ")+" (default: chosen from the command)")
+	flag.Var(&servers, "server", "Additional language server as 'languages=command args', e.g. 'go=gopls' or 'typescript,javascript=typescript-language-server --stdio'. Globs such as '**/*.py' may be used instead of languages. Can be repeated.")
+	flag.DurationVar(&cfg.requestTimeout, "request-timeout", lsp.DefaultRequestTimeout, "Maximum time to wait for a response from the language server (0 disables the timeout)")
+	flag.StringVar(&cfg.transport, "transport", transportStdio, "MCP transport: stdio, or http to serve several MCP clients over HTTP with server-sent events")
+	flag.StringVar(&cfg.listen, "listen", "localhost:7777", "Address for the http transport, as host:port or unix:/path/to/socket")
+	flag.StringVar(&cfg.authToken, "auth-token", "", "Bearer token required by the http transport (default: $"+authTokenEnv+")")
+	flag.BoolVar(&cfg.formatAfterEdit, "format-after-edit", false, "Format files with the language server after edit_file changes them")
 	flag.Parse()
 
 	// Get remaining args after -- as LSP arguments
 	cfg.lspArgs = flag.Args()
 
+	switch cfg.transport {
+	case transportStdio:
+	case transportHTTP, "sse":
+		cfg.transport = transportHTTP
+		if cfg.authToken == "" {
+			cfg.authToken = os.Getenv(authTokenEnv)
+		}
+	default:
+		return nil, fmt.Errorf("unknown transport %q, expected stdio or http", cfg.transport)
+	}
+
 	// Validate workspace directory
 	if cfg.workspaceDir == "" {
 		return nil, fmt.Errorf("workspace directory is required")
@@ -59,18 +124,154 @@ func parseConfig() (*config, error) {
 		return nil, fmt.Errorf("workspace directory does not exist: %s", cfg.workspaceDir)
 	}
 
-	// Validate LSP command
-	if cfg.lspCommand == "" {
+	for _, spec := range servers {
+		server, err := parseServerSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		cfg.servers = append(cfg.servers, server)
+	}
+
+	if cfg.configPath == "" {
+		cfg.configPath = configfile.Find(cfg.workspaceDir)
+	}
+	if cfg.configPath != "" {
+		if err := cfg.applyConfigFile(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.settingsPath != "" {
+		// --settings is relative to the workspace. The configuration file's
+		// settingsFile was already resolved against the file.
+		if !filepath.IsAbs(cfg.settingsPath) {
+			cfg.settingsPath = filepath.Join(cfg.workspaceDir, cfg.settingsPath)
+		}
+		settings, err := lsp.LoadSettings(cfg.settingsPath, cfg.workspaceDir)
+		if err != nil {
+			return nil, err
+		}
+		cfg.settings = settings
+	}
+
+	// The --lsp server handles every file not claimed by a --server
+	if cfg.lspCommand != "" {
+		cfg.servers = append(cfg.servers, serverConfig{
+			name:    filepath.Base(cfg.lspCommand),
+			command: cfg.lspCommand,
+			args:    cfg.lspArgs,
+			profile: cfg.lspProfile,
+		})
+	} else if cfg.lspProfile != "" {
+		return nil, fmt.Errorf("--profile requires --lsp")
+	}
+
+	// Validate LSP commands
+	if len(cfg.servers) == 0 {
 		return nil, fmt.Errorf("LSP command is required")
 	}
 
-	if _, err := exec.LookPath(cfg.lspCommand); err != nil {
-		return nil, fmt.Errorf("LSP command not found: %s", cfg.lspCommand)
+	for i, server := range cfg.servers {
+		if _, err := exec.LookPath(server.command); err != nil {
+			return nil, fmt.Errorf("LSP command not found: %s", server.command)
+		}
+		if server.profile != "" {
+			profile, err := lsp.LookupProfile(server.profile)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", server.name, err)
+			}
+			if server.client == nil {
+				cfg.servers[i].client = lsp.DefaultClientConfig()
+			}
+			cfg.servers[i].client.Profile = profile
+		}
 	}
 
 	return cfg, nil
 }
 
+// applyConfigFile loads the configuration file. Its servers are routed after
+// --server flags and before --lsp, and command line flags take precedence.
+func (cfg *config) applyConfigFile() error {
+	file, err := configfile.Load(cfg.configPath)
+	if err != nil {
+		return err
+	}
+	coreLogger.Info("Using configuration file %s", cfg.configPath)
+
+	if err := file.Logging.Apply(); err != nil {
+		return fmt.Errorf("failed to apply logging config: %v", err)
+	}
+
+	for _, server := range file.Servers {
+		cfg.servers = append(cfg.servers, serverConfig{
+			name:      server.ServerName(),
+			command:   server.Command,
+			args:      server.Args,
+			languages: server.Languages,
+			globs:     server.Globs,
+			profile:   server.Profile,
+			client: &lsp.ClientConfig{
+				Env:                   server.Env,
+				InitializationOptions: server.InitializationOptions,
+				Settings:              server.Settings,
+			},
+		})
+	}
+
+	if file.SettingsFile != "" && cfg.settingsPath == "" {
+		cfg.settingsPath = file.SettingsFile
+	}
+	if file.RequestTimeout != nil && !isFlagSet("request-timeout") {
+		cfg.requestTimeout = file.RequestTimeout.Duration
+	}
+	cfg.watcher = file.Watcher
+	cfg.tools = file.Tools
+	if file.Tools.FormatAfterEdit && !isFlagSet("format-after-edit") {
+		cfg.formatAfterEdit = true
+	}
+	return nil
+}
+
+// isFlagSet reports whether a flag was given on the command line
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// parseServerSpec parses a --server flag of the form 'languages=command args'
+func parseServerSpec(spec string) (serverConfig, error) {
+	matchers, commandLine, ok := strings.Cut(spec, "=")
+	fields := strings.Fields(commandLine)
+	if !ok || len(fields) == 0 || strings.TrimSpace(matchers) == "" {
+		return serverConfig{}, fmt.Errorf("invalid server %q, expected 'languages=command args'", spec)
+	}
+
+	server := serverConfig{
+		name:    filepath.Base(fields[0]),
+		command: fields[0],
+		args:    fields[1:],
+	}
+	for _, matcher := range strings.Split(matchers, ",") {
+		matcher = strings.TrimSpace(matcher)
+		if matcher == "" {
+			continue
+		}
+		// Language IDs are plain words, anything else is a file glob
+		if strings.ContainsAny(matcher, "*?[/.") {
+			server.globs = append(server.globs, matcher)
+		} else {
+			server.languages = append(server.languages, matcher)
+		}
+	}
+	return server, nil
+}
+
 func newServer(config *config) (*mcpServer, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &mcpServer{
@@ -85,29 +286,76 @@ func (s *mcpServer) initializeLSP() error {
 		return fmt.Errorf("failed to change to workspace directory: %v", err)
 	}
 
-	client, err := lsp.NewClient(s.config.lspCommand, s.config.lspArgs...)
-	if err != nil {
-		return fmt.Errorf("failed to create LSP client: %v", err)
+	var routes []*lsp.Route
+	for _, server := range s.config.servers {
+		client, err := lsp.NewClientWithConfig(server.client, server.command, server.args...)
+		if err != nil {
+			s.router = lsp.NewRouter(s.config.workspaceDir, routes...)
+			return fmt.Errorf("failed to create LSP client for %s: %v", server.name, err)
+		}
+		client.SetRequestTimeout(s.config.requestTimeout)
+		if s.config.settings != nil {
+			client.Settings().Set(s.config.settings)
+		}
+		routes = append(routes, &lsp.Route{
+			Name:        server.name,
+			Client:      client,
+			LanguageIDs: server.languages,
+			Globs:       server.globs,
+		})
 	}
-	s.lspClient = client
-	s.workspaceWatcher = watcher.NewWorkspaceWatcher(client)
+	s.router = lsp.NewRouter(s.config.workspaceDir, routes...)
 
-	initResult, err := client.InitializeLSPClient(s.ctx, s.config.workspaceDir)
-	if err != nil {
-		return fmt.Errorf("initialize failed: %v", err)
-	}
+	// One watcher serves every server, routing files by type
+	watcherConfig := watcher.DefaultWatcherConfig()
+	s.config.watcher.Apply(watcherConfig)
+	s.workspaceWatcher = watcher.NewWorkspaceWatcherWithConfig(s.router, watcherConfig)
 
-	coreLogger.Debug("Server capabilities: %+v", initResult.Capabilities)
+	for _, route := range routes {
+		initResult, err := route.Client.InitializeLSPClient(s.ctx, s.config.workspaceDir)
+		if err != nil {
+			return fmt.Errorf("initialize failed for %s: %v", route.Name, err)
+		}
+
+		coreLogger.Debug("Server capabilities for %s: %+v", route.Name, initResult.Capabilities)
+
+		// Restart the server if it crashes, including while it is indexing
+		go lsp.NewSupervisor(route.Client, s.config.workspaceDir).Run(s.ctx)
+	}
 
 	go s.workspaceWatcher.WatchWorkspace(s.ctx, s.config.workspaceDir)
-	return client.WaitForServerReady(s.ctx)
+
+	if s.config.settingsPath != "" {
+		err := lsp.WatchSettingsFile(s.ctx, s.config.settingsPath, s.config.workspaceDir, s.updateSettings)
+		if err != nil {
+			coreLogger.Warn("Settings file changes will not be applied: %v", err)
+		}
+	}
+
+	// Servers index independently, so wait for them together
+	var wg sync.WaitGroup
+	errs := make([]error, len(routes))
+	for i, route := range routes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = route.Client.WaitForServerReady(s.ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
-func (s *mcpServer) start() error {
-	if err := s.initializeLSP(); err != nil {
-		return err
+// updateSettings sends changed settings to every language server
+func (s *mcpServer) updateSettings(settings *lsp.Settings) {
+	for _, route := range s.router.Routes() {
+		if err := route.Client.UpdateSettings(s.ctx, settings); err != nil {
+			coreLogger.Error("Failed to update settings for %s: %v", route.Name, err)
+		}
 	}
+}
 
+func (s *mcpServer) start() error {
 	s.mcpServer = server.NewMCPServer(
 		"MCP Language Server",
 		"v0.0.2",
@@ -115,11 +363,20 @@ func (s *mcpServer) start() error {
 		server.WithRecovery(),
 	)
 
+	// Register tools first so configuration mistakes are reported before
+	// waiting for the language servers
 	err := s.registerTools()
 	if err != nil {
 		return fmt.Errorf("tool registration failed: %v", err)
 	}
 
+	if err := s.initializeLSP(); err != nil {
+		return err
+	}
+
+	if s.config.transport == transportHTTP {
+		return s.serveHTTP()
+	}
 	return server.ServeStdio(s.mcpServer)
 }
 
@@ -193,45 +450,27 @@ func main() {
 func cleanup(s *mcpServer, done chan struct{}) {
 	coreLogger.Info("Cleanup initiated for PID: %d", os.Getpid())
 
+	// Stop background work, including the supervisor, so the language server
+	// exiting below isn't mistaken for a crash
+	s.cancelFunc()
+
 	// Create a context with timeout for shutdown operations
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if s.lspClient != nil {
-		coreLogger.Info("Closing open files")
-		s.lspClient.CloseAllFiles(ctx)
-
-		// Create a shorter timeout context for the shutdown request
-		shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 500*time.Millisecond)
-		defer shutdownCancel()
-
-		// Run shutdown in a goroutine with timeout to avoid blocking if LSP doesn't respond
-		shutdownDone := make(chan struct{})
-		go func() {
-			coreLogger.Info("Sending shutdown request")
-			if err := s.lspClient.Shutdown(shutdownCtx); err != nil {
-				coreLogger.Error("Shutdown request failed: %v", err)
-			}
-			close(shutdownDone)
-		}()
-
-		// Wait for shutdown with timeout
-		select {
-		case <-shutdownDone:
-			coreLogger.Info("Shutdown request completed")
-		case <-time.After(1 * time.Second):
-			coreLogger.Warn("Shutdown request timed out, proceeding with exit")
-		}
-
-		coreLogger.Info("Sending exit notification")
-		if err := s.lspClient.Exit(ctx); err != nil {
-			coreLogger.Error("Exit notification failed: %v", err)
-		}
-
-		coreLogger.Info("Closing LSP client")
-		if err := s.lspClient.Close(); err != nil {
-			coreLogger.Error("Failed to close LSP client: %v", err)
+	// Stop taking requests before the language servers go away
+	s.shutdownHTTP(ctx)
+
+	if s.router != nil {
+		var wg sync.WaitGroup
+		for _, client := range s.router.Clients() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				shutdownClient(ctx, client)
+			}()
 		}
+		wg.Wait()
 	}
 
 	// Send signal to the done channel
@@ -243,3 +482,41 @@ func cleanup(s *mcpServer, done chan struct{}) {
 
 	coreLogger.Info("Cleanup completed for PID: %d", os.Getpid())
 }
+
+// shutdownClient closes a language server's files and asks it to exit
+func shutdownClient(ctx context.Context, client *lsp.Client) {
+	coreLogger.Info("Closing open files")
+	client.CloseAllFiles(ctx)
+
+	// Create a shorter timeout context for the shutdown request
+	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer shutdownCancel()
+
+	// Run shutdown in a goroutine with timeout to avoid blocking if LSP doesn't respond
+	shutdownDone := make(chan struct{})
+	go func() {
+		coreLogger.Info("Sending shutdown request")
+		if err := client.Shutdown(shutdownCtx); err != nil {
+			coreLogger.Error("Shutdown request failed: %v", err)
+		}
+		close(shutdownDone)
+	}()
+
+	// Wait for shutdown with timeout
+	select {
+	case <-shutdownDone:
+		coreLogger.Info("Shutdown request completed")
+	case <-time.After(1 * time.Second):
+		coreLogger.Warn("Shutdown request timed out, proceeding with exit")
+	}
+
+	coreLogger.Info("Sending exit notification")
+	if err := client.Exit(ctx); err != nil {
+		coreLogger.Error("Exit notification failed: %v", err)
+	}
+
+	coreLogger.Info("Closing LSP client")
+	if err := client.Close(); err != nil {
+		coreLogger.Error("Failed to close LSP client: %v", err)
+	}
+}
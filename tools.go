@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/isaacphi/mcp-language-server/internal/tools"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 )
 
 func (s *mcpServer) registerTools() error {
@@ -41,7 +45,7 @@ func (s *mcpServer) registerTools() error {
 		),
 	)
 
-	s.mcpServer.AddTool(applyTextEditTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(applyTextEditTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract arguments
 		filePath, ok := request.Params.Arguments["filePath"].(string)
 		if !ok {
@@ -86,15 +90,118 @@ func (s *mcpServer) registerTools() error {
 			})
 		}
 
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		coreLogger.Debug("Executing edit_file for file: %s", filePath)
-		response, err := tools.ApplyTextEdits(s.ctx, s.lspClient, filePath, edits)
+		response, err := tools.ApplyTextEdits(ctx, client, filePath, edits)
 		if err != nil {
 			coreLogger.Error("Failed to apply edits: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to apply edits: %v", err)), nil
 		}
+
+		if s.config.formatAfterEdit {
+			// The edits were applied, so a formatting failure is only reported
+			formatted, err := tools.FormatFile(ctx, client, filePath, 0, 0, tools.FormatOptions{})
+			if err != nil {
+				coreLogger.Warn("Failed to format %s after editing: %v", filePath, err)
+				formatted = fmt.Sprintf("Formatting failed: %v", err)
+			}
+			response += "\n" + formatted
+		}
 		return mcp.NewToolResultText(response), nil
 	})
 
+	formatTool := mcp.NewTool("format",
+		mcp.WithDescription("Format a file, or a range of lines in it, with the language server's formatter. Indentation is detected from the file unless given."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to format"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Description("First line to format (1-indexed). Formats the whole file if not given."),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("Last line to format (1-indexed), inclusive. Defaults to startLine."),
+		),
+		mcp.WithNumber("tabSize",
+			mcp.Description("Size of a tab in spaces"),
+		),
+		mcp.WithBoolean("insertSpaces",
+			mcp.Description("Indent with spaces instead of tabs"),
+		),
+		mcp.WithBoolean("trimTrailingWhitespace",
+			mcp.Description("Remove whitespace at the end of lines"),
+		),
+		mcp.WithBoolean("insertFinalNewline",
+			mcp.Description("End the file with a newline"),
+		),
+		mcp.WithBoolean("trimFinalNewlines",
+			mcp.Description("Remove blank lines at the end of the file"),
+		),
+	)
+
+	s.addTool(formatTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for startLine, endLine and tabSize due to JSON parsing
+		var startLine, endLine int
+		switch v := request.Params.Arguments["startLine"].(type) {
+		case float64:
+			startLine = int(v)
+		case int:
+			startLine = v
+		}
+
+		switch v := request.Params.Arguments["endLine"].(type) {
+		case float64:
+			endLine = int(v)
+		case int:
+			endLine = v
+		}
+
+		var options tools.FormatOptions
+		switch v := request.Params.Arguments["tabSize"].(type) {
+		case float64:
+			tabSize := int(v)
+			options.TabSize = &tabSize
+		case int:
+			options.TabSize = &v
+		}
+
+		if v, ok := request.Params.Arguments["insertSpaces"].(bool); ok {
+			options.InsertSpaces = &v
+		}
+		if v, ok := request.Params.Arguments["trimTrailingWhitespace"].(bool); ok {
+			options.TrimTrailingWhitespace = &v
+		}
+		if v, ok := request.Params.Arguments["insertFinalNewline"].(bool); ok {
+			options.InsertFinalNewline = &v
+		}
+		if v, ok := request.Params.Arguments["trimFinalNewlines"].(bool); ok {
+			options.TrimFinalNewlines = &v
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing format for file: %s", filePath)
+		text, err := tools.FormatFile(ctx, client, filePath, startLine, endLine, options)
+		if err != nil {
+			coreLogger.Error("Failed to format file: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format file: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
 	readDefinitionTool := mcp.NewTool("definition",
 		mcp.WithDescription("Read the source code definition of a symbol (function, type, constant, etc.) from the codebase. Returns the complete implementation code where the symbol is defined."),
 		mcp.WithString("symbolName",
@@ -103,7 +210,7 @@ func (s *mcpServer) registerTools() error {
 		),
 	)
 
-	s.mcpServer.AddTool(readDefinitionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(readDefinitionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract arguments
 		symbolName, ok := request.Params.Arguments["symbolName"].(string)
 		if !ok {
@@ -111,7 +218,7 @@ func (s *mcpServer) registerTools() error {
 		}
 
 		coreLogger.Debug("Executing definition for symbol: %s", symbolName)
-		text, err := tools.ReadDefinition(s.ctx, s.lspClient, symbolName)
+		text, err := tools.ReadDefinitionAcrossServers(ctx, s.router.Clients(), symbolName)
 		if err != nil {
 			coreLogger.Error("Failed to get definition: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to get definition: %v", err)), nil
@@ -119,23 +226,134 @@ func (s *mcpServer) registerTools() error {
 		return mcp.NewToolResultText(text), nil
 	})
 
+	goToDefinitionTool := mcp.NewTool("go_to_definition",
+		mcp.WithDescription("Go to the definition, type definition or declaration of the symbol at a position and return its full source code. Works for local variables, fields and parameters, and for symbols defined in dependencies outside the workspace."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file containing the symbol"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number of the symbol (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number of the symbol (1-indexed)"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("Where to go: the symbol's 'definition', the definition of its type ('typeDefinition') or its 'declaration'. Defaults to 'definition'."),
+			mcp.Enum(tools.NavigateDefinition, tools.NavigateTypeDefinition, tools.NavigateDeclaration),
+		),
+	)
+
+	s.addTool(goToDefinitionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for line and column due to JSON parsing
+		var line, column int
+		switch v := request.Params.Arguments["line"].(type) {
+		case float64:
+			line = int(v)
+		case int:
+			line = v
+		default:
+			return mcp.NewToolResultError("line must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["column"].(type) {
+		case float64:
+			column = int(v)
+		case int:
+			column = v
+		default:
+			return mcp.NewToolResultError("column must be a number"), nil
+		}
+
+		kind, _ := request.Params.Arguments["kind"].(string)
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing go_to_definition for file: %s line: %d column: %d kind: %s", filePath, line, column, kind)
+		text, err := tools.GoToDefinition(ctx, client, filePath, line, column, kind)
+		if err != nil {
+			coreLogger.Error("Failed to go to definition: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to go to definition: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
 	findReferencesTool := mcp.NewTool("references",
-		mcp.WithDescription("Find all usages and references of a symbol throughout the codebase. Returns a list of all files and locations where the symbol appears."),
+		mcp.WithDescription("Find all usages and references of a symbol throughout the codebase. Returns a list of all files and locations where the symbol appears. Identify the symbol by symbolName, or by filePath, line and column for locals, fields and other symbols that can't be found by name."),
 		mcp.WithString("symbolName",
-			mcp.Required(),
 			mcp.Description("The name of the symbol to search for (e.g. 'mypackage.MyFunction', 'MyType')"),
 		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to a file containing the symbol, used with line and column instead of symbolName"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("The line number of the symbol (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Description("The column number of the symbol (1-indexed)"),
+		),
+		mcp.WithBoolean("includeDeclaration",
+			mcp.Description("Also list the symbol's declaration when finding it by position. Defaults to false."),
+		),
 	)
 
-	s.mcpServer.AddTool(findReferencesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(findReferencesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract arguments
-		symbolName, ok := request.Params.Arguments["symbolName"].(string)
-		if !ok {
-			return mcp.NewToolResultError("symbolName must be a string"), nil
+		if symbolName, ok := request.Params.Arguments["symbolName"].(string); ok && symbolName != "" {
+			coreLogger.Debug("Executing references for symbol: %s", symbolName)
+			text, err := tools.FindReferencesAcrossServers(ctx, s.router.Clients(), symbolName)
+			if err != nil {
+				coreLogger.Error("Failed to find references: %v", err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to find references: %v", err)), nil
+			}
+			return mcp.NewToolResultText(text), nil
+		}
+
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok || filePath == "" {
+			return mcp.NewToolResultError("either symbolName or filePath, line and column must be given"), nil
+		}
+
+		// Handle both float64 and int for line and column due to JSON parsing
+		var line, column int
+		switch v := request.Params.Arguments["line"].(type) {
+		case float64:
+			line = int(v)
+		case int:
+			line = v
+		default:
+			return mcp.NewToolResultError("line must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["column"].(type) {
+		case float64:
+			column = int(v)
+		case int:
+			column = v
+		default:
+			return mcp.NewToolResultError("column must be a number"), nil
+		}
+
+		includeDeclaration, _ := request.Params.Arguments["includeDeclaration"].(bool)
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		coreLogger.Debug("Executing references for symbol: %s", symbolName)
-		text, err := tools.FindReferences(s.ctx, s.lspClient, symbolName)
+		coreLogger.Debug("Executing references for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.FindReferencesAt(ctx, client, filePath, line, column, includeDeclaration)
 		if err != nil {
 			coreLogger.Error("Failed to find references: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to find references: %v", err)), nil
@@ -143,44 +361,698 @@ func (s *mcpServer) registerTools() error {
 		return mcp.NewToolResultText(text), nil
 	})
 
-	getDiagnosticsTool := mcp.NewTool("diagnostics",
-		mcp.WithDescription("Get diagnostic information for a specific file from the language server."),
+	documentHighlightTool := mcp.NewTool("document_highlight",
+		mcp.WithDescription("List the occurrences in one file of the symbol at a position, each marked as a read, a write or a textual match. Works for locals and parameters, and is cheaper than references for checking where a variable is changed within a function."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file containing the symbol"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number of the symbol (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number of the symbol (1-indexed)"),
+		),
+	)
+
+	s.addTool(documentHighlightTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for line and column due to JSON parsing
+		var line, column int
+		switch v := request.Params.Arguments["line"].(type) {
+		case float64:
+			line = int(v)
+		case int:
+			line = v
+		default:
+			return mcp.NewToolResultError("line must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["column"].(type) {
+		case float64:
+			column = int(v)
+		case int:
+			column = v
+		default:
+			return mcp.NewToolResultError("column must be a number"), nil
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing document_highlight for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.GetDocumentHighlights(ctx, client, filePath, line, column)
+		if err != nil {
+			coreLogger.Error("Failed to get document highlights: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get document highlights: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	workspaceSymbolsTool := mcp.NewTool("workspace_symbols",
+		mcp.WithDescription("Search for symbols across the workspace by approximate name. Returns ranked fuzzy matches with their kind, container and location. Use this when you don't know the exact name of a symbol."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The name or part of the name of the symbol to search for (e.g. 'Server', 'handleReq')"),
+		),
+		mcp.WithString("kinds",
+			mcp.Description("Comma-separated symbol kinds to include, e.g. 'class,function,method'"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of symbols to return"),
+			mcp.DefaultNumber(tools.DefaultWorkspaceSymbolsLimit),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of symbols to skip, for paging through results"),
+		),
+	)
+
+	s.addTool(workspaceSymbolsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		query, ok := request.Params.Arguments["query"].(string)
+		if !ok || query == "" {
+			return mcp.NewToolResultError("query must be a non-empty string"), nil
+		}
+
+		var kinds []string
+		if v, ok := request.Params.Arguments["kinds"].(string); ok && v != "" {
+			kinds = strings.Split(v, ",")
+		}
+
+		// Handle both float64 and int for limit and offset due to JSON parsing
+		limit := tools.DefaultWorkspaceSymbolsLimit
+		switch v := request.Params.Arguments["limit"].(type) {
+		case float64:
+			limit = int(v)
+		case int:
+			limit = v
+		}
+
+		var offset int
+		switch v := request.Params.Arguments["offset"].(type) {
+		case float64:
+			offset = int(v)
+		case int:
+			offset = v
+		}
+
+		coreLogger.Debug("Executing workspace_symbols for query: %s", query)
+		text, err := tools.SearchWorkspaceSymbols(ctx, s.router.Clients(), query, kinds, offset, limit)
+		if err != nil {
+			coreLogger.Error("Failed to search workspace symbols: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to search workspace symbols: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	getDiagnosticsTool := mcp.NewTool("diagnostics",
+		mcp.WithDescription("Get diagnostic information for a specific file from the language server."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to get diagnostics for"),
+		),
+		mcp.WithBoolean("contextLines",
+			mcp.Description("Lines to include around each diagnostic."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("showLineNumbers",
+			mcp.Description("If true, adds line numbers to the output"),
+			mcp.DefaultBool(true),
+		),
+	)
+
+	s.addTool(getDiagnosticsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		contextLines := 5 // default value
+		if contextLinesArg, ok := request.Params.Arguments["contextLines"].(int); ok {
+			contextLines = contextLinesArg
+		}
+
+		showLineNumbers := true // default value
+		if showLineNumbersArg, ok := request.Params.Arguments["showLineNumbers"].(bool); ok {
+			showLineNumbers = showLineNumbersArg
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing diagnostics for file: %s", filePath)
+		text, err := tools.GetDiagnosticsForFile(ctx, client, filePath, contextLines, showLineNumbers)
+		if err != nil {
+			coreLogger.Error("Failed to get diagnostics: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get diagnostics: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	workspaceDiagnosticsTool := mcp.NewTool("workspace_diagnostics",
+		mcp.WithDescription("Get diagnostics (errors, warnings, etc.) for every file in the workspace that the language server knows about, grouped by file. Use after a refactor to see what is broken in the project."),
+		mcp.WithString("severity",
+			mcp.Description("Least severe diagnostics to include: 'error', 'warning', 'info' or 'hint'. Defaults to 'warning'."),
+			mcp.Enum("error", "warning", "info", "hint"),
+		),
+		mcp.WithString("pathGlob",
+			mcp.Description("Only include files matching this glob, relative to the workspace (e.g. 'src/**/*.ts', '*.go')"),
+		),
+		mcp.WithString("source",
+			mcp.Description("Only include diagnostics from this source (e.g. 'compiler', 'eslint')"),
+		),
+		mcp.WithNumber("maxDiagnostics",
+			mcp.Description("Maximum number of diagnostics to return"),
+			mcp.DefaultNumber(tools.DefaultMaxWorkspaceDiagnostics),
+		),
+	)
+
+	s.addTool(workspaceDiagnosticsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		severity, _ := request.Params.Arguments["severity"].(string)
+		if severity == "" {
+			severity = "warning"
+		}
+		minSeverity, err := tools.ParseSeverity(severity)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		filter := tools.WorkspaceDiagnosticsFilter{MinSeverity: minSeverity}
+		filter.PathGlob, _ = request.Params.Arguments["pathGlob"].(string)
+		filter.Source, _ = request.Params.Arguments["source"].(string)
+
+		// Handle both float64 and int for maxDiagnostics due to JSON parsing
+		maxDiagnostics := tools.DefaultMaxWorkspaceDiagnostics
+		switch v := request.Params.Arguments["maxDiagnostics"].(type) {
+		case float64:
+			maxDiagnostics = int(v)
+		case int:
+			maxDiagnostics = v
+		}
+
+		coreLogger.Debug("Executing workspace_diagnostics with filter: %+v", filter)
+		text, err := tools.GetWorkspaceDiagnostics(ctx, s.router.Clients(), filter, maxDiagnostics)
+		if err != nil {
+			coreLogger.Error("Failed to get workspace diagnostics: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get workspace diagnostics: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	codeActionsTool := mcp.NewTool("code_actions",
+		mcp.WithDescription("List the code actions the language server offers for lines of a file, such as quick fixes for diagnostics (adding a missing import, removing an unused variable), refactorings and source actions. To fix a diagnostic, pass its line. Apply an action with apply_code_action."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Required(),
+			mcp.Description("First line of the range (1-indexed)"),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("Last line of the range (1-indexed), inclusive. Defaults to startLine."),
+		),
+		mcp.WithString("kinds",
+			mcp.Description("Comma-separated code action kinds to include, e.g. 'quickfix' or 'refactor.extract,source.organizeImports'"),
+		),
+	)
+
+	s.addTool(codeActionsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for startLine and endLine due to JSON parsing
+		var startLine, endLine int
+		switch v := request.Params.Arguments["startLine"].(type) {
+		case float64:
+			startLine = int(v)
+		case int:
+			startLine = v
+		default:
+			return mcp.NewToolResultError("startLine must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["endLine"].(type) {
+		case float64:
+			endLine = int(v)
+		case int:
+			endLine = v
+		}
+
+		var kinds []string
+		if v, ok := request.Params.Arguments["kinds"].(string); ok && v != "" {
+			kinds = strings.Split(v, ",")
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing code_actions for file: %s lines: %d-%d", filePath, startLine, endLine)
+		text, err := tools.ListCodeActions(ctx, client, filePath, startLine, endLine, kinds)
+		if err != nil {
+			coreLogger.Error("Failed to get code actions: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get code actions: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	applyCodeActionTool := mcp.NewTool("apply_code_action",
+		mcp.WithDescription("Apply one of the code actions listed by code_actions, editing files as needed. Pass the same filePath, lines and kinds used to list the actions, and either the action's title or its number in the list."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Required(),
+			mcp.Description("First line of the range (1-indexed)"),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("Last line of the range (1-indexed), inclusive. Defaults to startLine."),
+		),
+		mcp.WithString("kinds",
+			mcp.Description("Comma-separated code action kinds, as passed to code_actions"),
+		),
+		mcp.WithString("title",
+			mcp.Description("The exact title of the code action to apply"),
+		),
+		mcp.WithNumber("index",
+			mcp.Description("The number of the code action in the list (1-indexed), used if title is not given"),
+		),
+	)
+
+	s.addTool(applyCodeActionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for startLine, endLine and index due to JSON parsing
+		var startLine, endLine, index int
+		switch v := request.Params.Arguments["startLine"].(type) {
+		case float64:
+			startLine = int(v)
+		case int:
+			startLine = v
+		default:
+			return mcp.NewToolResultError("startLine must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["endLine"].(type) {
+		case float64:
+			endLine = int(v)
+		case int:
+			endLine = v
+		}
+
+		switch v := request.Params.Arguments["index"].(type) {
+		case float64:
+			index = int(v)
+		case int:
+			index = v
+		}
+
+		var kinds []string
+		if v, ok := request.Params.Arguments["kinds"].(string); ok && v != "" {
+			kinds = strings.Split(v, ",")
+		}
+
+		title, _ := request.Params.Arguments["title"].(string)
+		if title == "" && index == 0 {
+			return mcp.NewToolResultError("either title or index must be given"), nil
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing apply_code_action for file: %s lines: %d-%d", filePath, startLine, endLine)
+		text, err := tools.ApplyCodeAction(ctx, client, filePath, startLine, endLine, kinds, index, title)
+		if err != nil {
+			coreLogger.Error("Failed to apply code action: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to apply code action: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	sourceActionTool := mcp.NewTool("source_action",
+		mcp.WithDescription("Organize imports or apply all automatic fixes in one or more files using the language server. Use after editing files to clean up imports everywhere at once instead of editing import blocks by hand."),
+		mcp.WithArray("filePaths",
+			mcp.Required(),
+			mcp.Description("The paths of the files to update"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("action",
+			mcp.Description("'organizeImports' to sort, add and remove imports, or 'fixAll' to apply every automatic fix. Defaults to 'organizeImports'."),
+			mcp.Enum(tools.SourceOrganizeImports, tools.SourceFixAll),
+		),
+	)
+
+	s.addTool(sourceActionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		pathsArg, ok := request.Params.Arguments["filePaths"].([]any)
+		if !ok || len(pathsArg) == 0 {
+			return mcp.NewToolResultError("filePaths must be a non-empty array"), nil
+		}
+
+		var filePaths []string
+		for _, path := range pathsArg {
+			filePath, ok := path.(string)
+			if !ok {
+				return mcp.NewToolResultError("each file path must be a string"), nil
+			}
+			filePaths = append(filePaths, filePath)
+		}
+
+		action, _ := request.Params.Arguments["action"].(string)
+		if action == "" {
+			action = tools.SourceOrganizeImports
+		}
+
+		// A failure in one file doesn't stop the others
+		var results []string
+		for _, filePath := range filePaths {
+			client, err := s.router.ClientForFile(filePath)
+			if err != nil {
+				results = append(results, fmt.Sprintf("%s: %v", filePath, err))
+				continue
+			}
+
+			coreLogger.Debug("Executing source_action %s for file: %s", action, filePath)
+			text, err := tools.ApplySourceAction(ctx, client, filePath, action)
+			if err != nil {
+				coreLogger.Error("Failed to apply source action: %v", err)
+				results = append(results, fmt.Sprintf("%s: failed to apply %s: %v", filePath, action, err))
+				continue
+			}
+			results = append(results, strings.TrimSuffix(text, "\n"))
+		}
+		return mcp.NewToolResultText(strings.Join(results, "\n")), nil
+	})
+
+	documentSymbolsTool := mcp.NewTool("document_symbols",
+		mcp.WithDescription("Get an outline of the symbols (classes, functions, methods, fields, etc.) in a file with their kinds and line ranges. Use this to see what a file contains without reading all of it."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to get symbols for"),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("How many levels of nested symbols to show, e.g. 1 for top-level symbols only. Defaults to all levels."),
+		),
+		mcp.WithString("kinds",
+			mcp.Description("Comma-separated symbol kinds to include, e.g. 'class,function,method'. Containing symbols are kept for context."),
+		),
+	)
+
+	s.addTool(documentSymbolsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for maxDepth due to JSON parsing
+		var maxDepth int
+		switch v := request.Params.Arguments["maxDepth"].(type) {
+		case float64:
+			maxDepth = int(v)
+		case int:
+			maxDepth = v
+		}
+
+		var kinds []string
+		if v, ok := request.Params.Arguments["kinds"].(string); ok && v != "" {
+			kinds = strings.Split(v, ",")
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing document_symbols for file: %s", filePath)
+		text, err := tools.GetDocumentSymbols(ctx, client, filePath, maxDepth, kinds)
+		if err != nil {
+			coreLogger.Error("Failed to get document symbols: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get document symbols: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	callHierarchyTool := mcp.NewTool("call_hierarchy",
+		mcp.WithDescription("Show which functions call a function (incoming) and which functions it calls (outgoing), as a tree with the lines where the calls are made. Use this to assess the impact of changing a function. Identify the function by symbolName, or by filePath, line and column."),
+		mcp.WithString("symbolName",
+			mcp.Description("The name of the function or method, e.g. 'handleRequest' or 'Server.Start'"),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to a file containing the function, used with line and column instead of symbolName"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("The line number of the function name (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Description("The column number of the function name (1-indexed)"),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Whether to show callers ('incoming'), callees ('outgoing') or 'both'. Defaults to 'incoming'."),
+			mcp.Enum(tools.CallsIncoming, tools.CallsOutgoing, tools.CallsBoth),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("How many levels of calls to follow, up to 5"),
+			mcp.DefaultNumber(tools.DefaultCallHierarchyDepth),
+		),
+	)
+
+	s.addTool(callHierarchyTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		direction, _ := request.Params.Arguments["direction"].(string)
+
+		// Handle both float64 and int for depth due to JSON parsing
+		depth := tools.DefaultCallHierarchyDepth
+		switch v := request.Params.Arguments["depth"].(type) {
+		case float64:
+			depth = int(v)
+		case int:
+			depth = v
+		}
+
+		if symbolName, ok := request.Params.Arguments["symbolName"].(string); ok && symbolName != "" {
+			coreLogger.Debug("Executing call_hierarchy for symbol: %s", symbolName)
+			text, err := tools.GetCallHierarchyForSymbol(ctx, s.router.Clients(), symbolName, direction, depth)
+			if err != nil {
+				coreLogger.Error("Failed to get call hierarchy: %v", err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get call hierarchy: %v", err)), nil
+			}
+			return mcp.NewToolResultText(text), nil
+		}
+
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok || filePath == "" {
+			return mcp.NewToolResultError("either symbolName or filePath, line and column must be given"), nil
+		}
+
+		// Handle both float64 and int for line and column due to JSON parsing
+		var line, column int
+		switch v := request.Params.Arguments["line"].(type) {
+		case float64:
+			line = int(v)
+		case int:
+			line = v
+		default:
+			return mcp.NewToolResultError("line must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["column"].(type) {
+		case float64:
+			column = int(v)
+		case int:
+			column = v
+		default:
+			return mcp.NewToolResultError("column must be a number"), nil
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing call_hierarchy for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.GetCallHierarchy(ctx, client, filePath, line, column, direction, depth)
+		if err != nil {
+			coreLogger.Error("Failed to get call hierarchy: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get call hierarchy: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	typeHierarchyTool := mcp.NewTool("type_hierarchy",
+		mcp.WithDescription("Show the supertypes (base classes and implemented interfaces) and subtypes (derived classes and implementations) of a type as a tree with file locations. Identify the type by symbolName, or by filePath, line and column."),
+		mcp.WithString("symbolName",
+			mcp.Description("The name of the type, e.g. 'Animal' or 'Shape'"),
+		),
+		mcp.WithString("filePath",
+			mcp.Description("The path to a file containing the type, used with line and column instead of symbolName"),
+		),
+		mcp.WithNumber("line",
+			mcp.Description("The line number of the type name (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Description("The column number of the type name (1-indexed)"),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Whether to show 'supertypes', 'subtypes' or 'both'. Defaults to 'both'."),
+			mcp.Enum(tools.TypesSupertypes, tools.TypesSubtypes, tools.TypesBoth),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("How many levels of types to follow, up to 10"),
+			mcp.DefaultNumber(tools.DefaultTypeHierarchyDepth),
+		),
+	)
+
+	s.addTool(typeHierarchyTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		direction, _ := request.Params.Arguments["direction"].(string)
+
+		// Handle both float64 and int for depth due to JSON parsing
+		depth := tools.DefaultTypeHierarchyDepth
+		switch v := request.Params.Arguments["depth"].(type) {
+		case float64:
+			depth = int(v)
+		case int:
+			depth = v
+		}
+
+		if symbolName, ok := request.Params.Arguments["symbolName"].(string); ok && symbolName != "" {
+			coreLogger.Debug("Executing type_hierarchy for symbol: %s", symbolName)
+			text, err := tools.GetTypeHierarchyForSymbol(ctx, s.router.Clients(), symbolName, direction, depth)
+			if err != nil {
+				coreLogger.Error("Failed to get type hierarchy: %v", err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get type hierarchy: %v", err)), nil
+			}
+			return mcp.NewToolResultText(text), nil
+		}
+
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok || filePath == "" {
+			return mcp.NewToolResultError("either symbolName or filePath, line and column must be given"), nil
+		}
+
+		// Handle both float64 and int for line and column due to JSON parsing
+		var line, column int
+		switch v := request.Params.Arguments["line"].(type) {
+		case float64:
+			line = int(v)
+		case int:
+			line = v
+		default:
+			return mcp.NewToolResultError("line must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["column"].(type) {
+		case float64:
+			column = int(v)
+		case int:
+			column = v
+		default:
+			return mcp.NewToolResultError("column must be a number"), nil
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing type_hierarchy for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.GetTypeHierarchy(ctx, client, filePath, line, column, direction, depth)
+		if err != nil {
+			coreLogger.Error("Failed to get type hierarchy: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get type hierarchy: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	implementationsTool := mcp.NewTool("implementations",
+		mcp.WithDescription("Find the concrete implementations of an interface, abstract class or abstract method and return their full source code. Identify the symbol by symbolName, or by filePath, line and column."),
+		mcp.WithString("symbolName",
+			mcp.Description("The name of the interface or method, e.g. 'Reader' or 'Shape.Area'"),
+		),
 		mcp.WithString("filePath",
-			mcp.Required(),
-			mcp.Description("The path to the file to get diagnostics for"),
+			mcp.Description("The path to a file containing the symbol, used with line and column instead of symbolName"),
 		),
-		mcp.WithBoolean("contextLines",
-			mcp.Description("Lines to include around each diagnostic."),
-			mcp.DefaultBool(false),
+		mcp.WithNumber("line",
+			mcp.Description("The line number of the symbol (1-indexed)"),
 		),
-		mcp.WithBoolean("showLineNumbers",
-			mcp.Description("If true, adds line numbers to the output"),
-			mcp.DefaultBool(true),
+		mcp.WithNumber("column",
+			mcp.Description("The column number of the symbol (1-indexed)"),
 		),
 	)
 
-	s.mcpServer.AddTool(getDiagnosticsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(implementationsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract arguments
+		if symbolName, ok := request.Params.Arguments["symbolName"].(string); ok && symbolName != "" {
+			coreLogger.Debug("Executing implementations for symbol: %s", symbolName)
+			text, err := tools.FindImplementationsForSymbol(ctx, s.router.Clients(), symbolName)
+			if err != nil {
+				coreLogger.Error("Failed to find implementations: %v", err)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to find implementations: %v", err)), nil
+			}
+			return mcp.NewToolResultText(text), nil
+		}
+
 		filePath, ok := request.Params.Arguments["filePath"].(string)
-		if !ok {
-			return mcp.NewToolResultError("filePath must be a string"), nil
+		if !ok || filePath == "" {
+			return mcp.NewToolResultError("either symbolName or filePath, line and column must be given"), nil
 		}
 
-		contextLines := 5 // default value
-		if contextLinesArg, ok := request.Params.Arguments["contextLines"].(int); ok {
-			contextLines = contextLinesArg
+		// Handle both float64 and int for line and column due to JSON parsing
+		var line, column int
+		switch v := request.Params.Arguments["line"].(type) {
+		case float64:
+			line = int(v)
+		case int:
+			line = v
+		default:
+			return mcp.NewToolResultError("line must be a number"), nil
 		}
 
-		showLineNumbers := true // default value
-		if showLineNumbersArg, ok := request.Params.Arguments["showLineNumbers"].(bool); ok {
-			showLineNumbers = showLineNumbersArg
+		switch v := request.Params.Arguments["column"].(type) {
+		case float64:
+			column = int(v)
+		case int:
+			column = v
+		default:
+			return mcp.NewToolResultError("column must be a number"), nil
 		}
 
-		coreLogger.Debug("Executing diagnostics for file: %s", filePath)
-		text, err := tools.GetDiagnosticsForFile(s.ctx, s.lspClient, filePath, contextLines, showLineNumbers)
+		client, err := s.router.ClientForFile(filePath)
 		if err != nil {
-			coreLogger.Error("Failed to get diagnostics: %v", err)
-			return mcp.NewToolResultError(fmt.Sprintf("failed to get diagnostics: %v", err)), nil
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing implementations for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.FindImplementations(ctx, client, filePath, line, column)
+		if err != nil {
+			coreLogger.Error("Failed to find implementations: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to find implementations: %v", err)), nil
 		}
 		return mcp.NewToolResultText(text), nil
 	})
@@ -195,15 +1067,20 @@ func (s *mcpServer) registerTools() error {
 	// 	),
 	// )
 	//
-	// s.mcpServer.AddTool(getCodeLensTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// s.addTool(getCodeLensTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// 	// Extract arguments
 	// 	filePath, ok := request.Params.Arguments["filePath"].(string)
 	// 	if !ok {
 	// 		return mcp.NewToolResultError("filePath must be a string"), nil
 	// 	}
 	//
+	// 	client, err := s.router.ClientForFile(filePath)
+	// 	if err != nil {
+	// 		return mcp.NewToolResultError(err.Error()), nil
+	// 	}
+	//
 	// 	coreLogger.Debug("Executing get_codelens for file: %s", filePath)
-	// 	text, err := tools.GetCodeLens(s.ctx, s.lspClient, filePath)
+	// 	text, err := tools.GetCodeLens(ctx, client, filePath)
 	// 	if err != nil {
 	// 		coreLogger.Error("Failed to get code lens: %v", err)
 	// 		return mcp.NewToolResultError(fmt.Sprintf("failed to get code lens: %v", err)), nil
@@ -223,7 +1100,7 @@ func (s *mcpServer) registerTools() error {
 	// 	),
 	// )
 	//
-	// s.mcpServer.AddTool(executeCodeLensTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// s.addTool(executeCodeLensTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// 	// Extract arguments
 	// 	filePath, ok := request.Params.Arguments["filePath"].(string)
 	// 	if !ok {
@@ -241,8 +1118,13 @@ func (s *mcpServer) registerTools() error {
 	// 		return mcp.NewToolResultError("index must be a number"), nil
 	// 	}
 	//
+	// 	client, err := s.router.ClientForFile(filePath)
+	// 	if err != nil {
+	// 		return mcp.NewToolResultError(err.Error()), nil
+	// 	}
+	//
 	// 	coreLogger.Debug("Executing execute_codelens for file: %s index: %d", filePath, index)
-	// 	text, err := tools.ExecuteCodeLens(s.ctx, s.lspClient, filePath, index)
+	// 	text, err := tools.ExecuteCodeLens(ctx, client, filePath, index)
 	// 	if err != nil {
 	// 		coreLogger.Error("Failed to execute code lens: %v", err)
 	// 		return mcp.NewToolResultError(fmt.Sprintf("failed to execute code lens: %v", err)), nil
@@ -266,7 +1148,7 @@ func (s *mcpServer) registerTools() error {
 		),
 	)
 
-	s.mcpServer.AddTool(hoverTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(hoverTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract arguments
 		filePath, ok := request.Params.Arguments["filePath"].(string)
 		if !ok {
@@ -293,8 +1175,67 @@ func (s *mcpServer) registerTools() error {
 			return mcp.NewToolResultError("column must be a number"), nil
 		}
 
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		coreLogger.Debug("Executing hover for file: %s line: %d column: %d", filePath, line, column)
-		text, err := tools.GetHoverInfo(s.ctx, s.lspClient, filePath, line, column)
+		text, err := tools.GetHoverInfo(ctx, client, filePath, line, column)
+		if err != nil {
+			coreLogger.Error("Failed to get hover information: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get hover information: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	batchHoverTool := mcp.NewTool("batch_hover",
+		mcp.WithDescription("Get hover information for every identifier in a range of lines at once. Returns a glossary with each distinct type or documentation once, listing the identifiers and lines it applies to. Use it instead of many hover calls to understand a function."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to get hover information for"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Required(),
+			mcp.Description("First line of the range (1-indexed)"),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("Last line of the range (1-indexed), inclusive. Defaults to startLine."),
+		),
+	)
+
+	s.addTool(batchHoverTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for startLine and endLine due to JSON parsing
+		var startLine, endLine int
+		switch v := request.Params.Arguments["startLine"].(type) {
+		case float64:
+			startLine = int(v)
+		case int:
+			startLine = v
+		default:
+			return mcp.NewToolResultError("startLine must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["endLine"].(type) {
+		case float64:
+			endLine = int(v)
+		case int:
+			endLine = v
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing batch_hover for file: %s lines: %d-%d", filePath, startLine, endLine)
+		text, err := tools.GetHoverGlossary(ctx, client, filePath, startLine, endLine)
 		if err != nil {
 			coreLogger.Error("Failed to get hover information: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to get hover information: %v", err)), nil
@@ -302,6 +1243,244 @@ func (s *mcpServer) registerTools() error {
 		return mcp.NewToolResultText(text), nil
 	})
 
+	inlayHintsTool := mcp.NewTool("inlay_hints",
+		mcp.WithDescription("Show lines of a file annotated with the language server's inlay hints, such as inferred variable types and parameter names, written inline between « and ». Use it to see the types in a function without hovering over each identifier."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to show"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Description("First line to show (1-indexed). Shows the whole file if not given."),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("Last line to show (1-indexed), inclusive. Defaults to startLine."),
+		),
+	)
+
+	s.addTool(inlayHintsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for startLine and endLine due to JSON parsing
+		var startLine, endLine int
+		switch v := request.Params.Arguments["startLine"].(type) {
+		case float64:
+			startLine = int(v)
+		case int:
+			startLine = v
+		}
+
+		switch v := request.Params.Arguments["endLine"].(type) {
+		case float64:
+			endLine = int(v)
+		case int:
+			endLine = v
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing inlay_hints for file: %s lines: %d-%d", filePath, startLine, endLine)
+		text, err := tools.GetInlayHints(ctx, client, filePath, startLine, endLine)
+		if err != nil {
+			coreLogger.Error("Failed to get inlay hints: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get inlay hints: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	semanticTokensTool := mcp.NewTool("semantic_tokens",
+		mcp.WithDescription("Read lines of a file with each identifier tagged by the language server with its kind (function, type, parameter, variable, etc.) and modifiers (definition, readonly, deprecated, etc.) between « and ». With declarationsOnly, list only the identifiers declared in the lines instead."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to read"),
+		),
+		mcp.WithNumber("startLine",
+			mcp.Description("First line to read (1-indexed). Reads the whole file if not given."),
+		),
+		mcp.WithNumber("endLine",
+			mcp.Description("Last line to read (1-indexed), inclusive. Defaults to startLine."),
+		),
+		mcp.WithBoolean("declarationsOnly",
+			mcp.Description("List only the declarations in the lines, with their positions, kinds and modifiers"),
+		),
+	)
+
+	s.addTool(semanticTokensTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for startLine and endLine due to JSON parsing
+		var startLine, endLine int
+		switch v := request.Params.Arguments["startLine"].(type) {
+		case float64:
+			startLine = int(v)
+		case int:
+			startLine = v
+		}
+
+		switch v := request.Params.Arguments["endLine"].(type) {
+		case float64:
+			endLine = int(v)
+		case int:
+			endLine = v
+		}
+
+		declarationsOnly, _ := request.Params.Arguments["declarationsOnly"].(bool)
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing semantic_tokens for file: %s lines: %d-%d", filePath, startLine, endLine)
+		text, err := tools.ReadSemanticTokens(ctx, client, filePath, startLine, endLine, declarationsOnly)
+		if err != nil {
+			coreLogger.Error("Failed to get semantic tokens: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get semantic tokens: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	signatureHelpTool := mcp.NewTool("signature_help",
+		mcp.WithDescription("Get the signatures of the function or method being called at a position, with their documentation and the parameter the position is in. Use it to check parameter order and types while writing a call."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file containing the call"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number inside the call's arguments (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number inside the call's arguments (1-indexed)"),
+		),
+	)
+
+	s.addTool(signatureHelpTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for line and column due to JSON parsing
+		var line, column int
+		switch v := request.Params.Arguments["line"].(type) {
+		case float64:
+			line = int(v)
+		case int:
+			line = v
+		default:
+			return mcp.NewToolResultError("line must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["column"].(type) {
+		case float64:
+			column = int(v)
+		case int:
+			column = v
+		default:
+			return mcp.NewToolResultError("column must be a number"), nil
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing signature_help for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.GetSignatureHelp(ctx, client, filePath, line, column)
+		if err != nil {
+			coreLogger.Error("Failed to get signature help: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get signature help: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
+	completionTool := mcp.NewTool("completion",
+		mcp.WithDescription("List the completions the language server offers at a position, such as the methods and fields after `value.` or the members of a package, with their kind, signature and documentation. Use it to discover APIs without reading their source."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to the file to complete in"),
+		),
+		mcp.WithNumber("line",
+			mcp.Required(),
+			mcp.Description("The line number to complete at (1-indexed)"),
+		),
+		mcp.WithNumber("column",
+			mcp.Required(),
+			mcp.Description("The column number to complete at (1-indexed), e.g. just after a `.`"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Only list completions containing this text (case-insensitive), those starting with it first"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of completions to list. Defaults to %d.", tools.DefaultCompletionLimit)),
+		),
+	)
+
+	s.addTool(completionTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract arguments
+		filePath, ok := request.Params.Arguments["filePath"].(string)
+		if !ok {
+			return mcp.NewToolResultError("filePath must be a string"), nil
+		}
+
+		// Handle both float64 and int for line and column due to JSON parsing
+		var line, column int
+		switch v := request.Params.Arguments["line"].(type) {
+		case float64:
+			line = int(v)
+		case int:
+			line = v
+		default:
+			return mcp.NewToolResultError("line must be a number"), nil
+		}
+
+		switch v := request.Params.Arguments["column"].(type) {
+		case float64:
+			column = int(v)
+		case int:
+			column = v
+		default:
+			return mcp.NewToolResultError("column must be a number"), nil
+		}
+
+		filter, _ := request.Params.Arguments["filter"].(string)
+
+		// Handle both float64 and int for limit due to JSON parsing
+		limit := tools.DefaultCompletionLimit
+		switch v := request.Params.Arguments["limit"].(type) {
+		case float64:
+			limit = int(v)
+		case int:
+			limit = v
+		}
+
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		coreLogger.Debug("Executing completion for file: %s line: %d column: %d", filePath, line, column)
+		text, err := tools.GetCompletions(ctx, client, filePath, line, column, filter, limit)
+		if err != nil {
+			coreLogger.Error("Failed to get completions: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get completions: %v", err)), nil
+		}
+		return mcp.NewToolResultText(text), nil
+	})
+
 	renameSymbolTool := mcp.NewTool("rename_symbol",
 		mcp.WithDescription("Rename a symbol (variable, function, class, etc.) at the specified position and update all references throughout the codebase."),
 		mcp.WithString("filePath",
@@ -322,7 +1501,7 @@ func (s *mcpServer) registerTools() error {
 		),
 	)
 
-	s.mcpServer.AddTool(renameSymbolTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(renameSymbolTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract arguments
 		filePath, ok := request.Params.Arguments["filePath"].(string)
 		if !ok {
@@ -354,8 +1533,13 @@ func (s *mcpServer) registerTools() error {
 			return mcp.NewToolResultError("column must be a number"), nil
 		}
 
+		client, err := s.router.ClientForFile(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		coreLogger.Debug("Executing rename_symbol for file: %s line: %d column: %d newName: %s", filePath, line, column, newName)
-		text, err := tools.RenameSymbol(s.ctx, s.lspClient, filePath, line, column, newName)
+		text, err := tools.RenameSymbol(ctx, client, filePath, line, column, newName)
 		if err != nil {
 			coreLogger.Error("Failed to rename symbol: %v", err)
 			return mcp.NewToolResultError(fmt.Sprintf("failed to rename symbol: %v", err)), nil
@@ -363,6 +1547,31 @@ func (s *mcpServer) registerTools() error {
 		return mcp.NewToolResultText(text), nil
 	})
 
+	if err := s.checkToolNames(); err != nil {
+		return err
+	}
+
 	coreLogger.Info("Successfully registered all MCP tools")
 	return nil
 }
+
+// addTool registers a tool unless the configuration disables it
+func (s *mcpServer) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	s.toolNames = append(s.toolNames, tool.Name)
+	if !s.config.tools.IsEnabled(tool.Name) {
+		coreLogger.Info("Tool %s disabled by configuration", tool.Name)
+		return
+	}
+	s.mcpServer.AddTool(tool, handler)
+}
+
+// checkToolNames reports tools named in the configuration that don't exist
+func (s *mcpServer) checkToolNames() error {
+	var errs []error
+	for _, name := range s.config.tools.Names() {
+		if !slices.Contains(s.toolNames, name) {
+			errs = append(errs, fmt.Errorf("unknown tool in configuration: %s", name))
+		}
+	}
+	return errors.Join(errs...)
+}
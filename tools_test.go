@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callTool calls a registered tool through the MCP server and returns its
+// result
+func callTool(t *testing.T, s *mcpServer, name string, arguments map[string]any) mcp.CallToolResult {
+	t.Helper()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": arguments},
+	})
+	require.NoError(t, err)
+
+	response, ok := s.mcpServer.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected a response, not an error")
+	result, ok := response.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+	return result
+}
+
+func TestReferencesRequiresSymbolOrPosition(t *testing.T) {
+	s := &mcpServer{
+		mcpServer: server.NewMCPServer("test", "v0"),
+		ctx:       context.Background(),
+	}
+	require.NoError(t, s.registerTools())
+
+	testCases := []struct {
+		name      string
+		arguments map[string]any
+	}{
+		{"no arguments", map[string]any{}},
+		{"empty symbol name", map[string]any{"symbolName": ""}},
+		{"position without file", map[string]any{"line": 3, "column": 5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := callTool(t, s, "references", tc.arguments)
+			assert.True(t, result.IsError)
+			require.Len(t, result.Content, 1)
+			text, ok := result.Content[0].(mcp.TextContent)
+			require.True(t, ok)
+			assert.Equal(t, "either symbolName or filePath, line and column must be given", text.Text)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
+)
+
+// authTokenEnv is read for the bearer token when --auth-token is not given,
+// which keeps the token out of the process list
+const authTokenEnv = "MCP_LANGUAGE_SERVER_TOKEN"
+
+// serveHTTP serves MCP over HTTP with server-sent events until the server is
+// shut down, so several MCP clients can share the language servers
+func (s *mcpServer) serveHTTP() error {
+	network, address := listenAddress(s.config.listen)
+	if network == "unix" {
+		// Remove a socket left behind by a previous run
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale socket: %v", err)
+		}
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", s.config.listen, err)
+	}
+
+	httpServer := &http.Server{}
+	sseServer := server.NewSSEServer(s.mcpServer,
+		server.WithHTTPServer(httpServer),
+		server.WithKeepAlive(true),
+	)
+	httpServer.Handler = requireBearerToken(s.config.authToken, sseServer)
+
+	s.httpMu.Lock()
+	if s.ctx.Err() != nil {
+		// Shutdown started while the language servers were initializing
+		s.httpMu.Unlock()
+		_ = listener.Close()
+		return nil
+	}
+	s.httpServer = sseServer
+	s.httpMu.Unlock()
+
+	coreLogger.Info("Serving MCP over HTTP on %s (SSE endpoint %s)", listener.Addr(), sseServer.CompleteSsePath())
+	if s.config.authToken == "" {
+		coreLogger.Warn("No auth token configured, any local process can use the server")
+	}
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// shutdownHTTP stops accepting MCP clients and closes their sessions
+func (s *mcpServer) shutdownHTTP(ctx context.Context) {
+	s.httpMu.Lock()
+	sseServer := s.httpServer
+	s.httpMu.Unlock()
+
+	if sseServer == nil {
+		return
+	}
+	coreLogger.Info("Shutting down HTTP server")
+	if err := sseServer.Shutdown(ctx); err != nil {
+		coreLogger.Error("HTTP server shutdown failed: %v", err)
+	}
+}
+
+// listenAddress splits a --listen value into a network and address. Unix
+// sockets are given as unix:/path/to/socket.
+func listenAddress(listen string) (string, string) {
+	if path, ok := strings.CutPrefix(listen, "unix:"); ok {
+		return "unix", path
+	}
+	return "tcp", listen
+}
+
+// requireBearerToken rejects requests without the token in an Authorization
+// header. An empty token allows every request.
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-language-server"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
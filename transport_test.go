@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireBearerToken(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	testCases := []struct {
+		name     string
+		token    string
+		header   string
+		expected int
+	}{
+		{"no token configured", "", "", http.StatusNoContent},
+		{"valid token", "secret", "Bearer secret", http.StatusNoContent},
+		{"missing header", "secret", "", http.StatusUnauthorized},
+		{"wrong token", "secret", "Bearer guess", http.StatusUnauthorized},
+		{"wrong scheme", "secret", "Basic secret", http.StatusUnauthorized},
+		{"token prefix", "secret", "Bearer secre", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+
+			requireBearerToken(tc.token, next).ServeHTTP(rec, req)
+			assert.Equal(t, tc.expected, rec.Code)
+			if tc.expected == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestListenAddress(t *testing.T) {
+	network, address := listenAddress("localhost:7777")
+	assert.Equal(t, "tcp", network)
+	assert.Equal(t, "localhost:7777", address)
+
+	network, address = listenAddress("unix:/tmp/mcp.sock")
+	assert.Equal(t, "unix", network)
+	assert.Equal(t, "/tmp/mcp.sock", address)
+}